@@ -4,8 +4,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
-
-	"github.com/gorilla/websocket"
 )
 
 func SendUnicastMessage(player *Player, msg MessageBase) error {
@@ -19,12 +17,12 @@ func SendUnicastMessage(player *Player, msg MessageBase) error {
 		return fmt.Errorf("failed to get schema storage: %w", err)
 	}
 
-	err = ss.validate(msg.GetType(), data)
+	err = ss.validate(GetSchemaId(msg), data)
 	if err != nil {
 		return fmt.Errorf("failed to validate outgoing %s message: %w", msg.GetType(), err)
 	}
 
-	err = player.conn.WriteMessage(websocket.TextMessage, data)
+	err = player.conn.Send(data)
 	if err != nil {
 		return fmt.Errorf("failed to send message %s to player %s: %w", msg.GetType(), player.id, err)
 	}
@@ -34,7 +32,33 @@ func SendUnicastMessage(player *Player, msg MessageBase) error {
 	return nil
 }
 
-func SendDirectErrorMessage(conn *websocket.Conn, errorMsg ErrorResponseMessage) {
+func SendDirectMessage(conn *Connection, msg MessageBase) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s message: %w", msg.GetType(), err)
+	}
+
+	ss, err := GetSchemaStorage()
+	if err != nil {
+		return fmt.Errorf("failed to get schema storage: %w", err)
+	}
+
+	err = ss.validate(GetSchemaId(msg), data)
+	if err != nil {
+		return fmt.Errorf("failed to validate outgoing %s message: %w", msg.GetType(), err)
+	}
+
+	err = conn.Send(data)
+	if err != nil {
+		return fmt.Errorf("failed to send message %s to client %s: %w", msg.GetType(), conn.RemoteAddr(), err)
+	}
+
+	slog.Debug("Outgoing direct message.", "type", msg.GetType(), "content", msg)
+
+	return nil
+}
+
+func SendDirectErrorMessage(conn *Connection, errorMsg ErrorResponseMessage) {
 	var err error
 
 	data, err := json.Marshal(errorMsg)
@@ -64,11 +88,11 @@ func SendDirectErrorMessage(conn *websocket.Conn, errorMsg ErrorResponseMessage)
 		return
 	}
 
-	err = conn.WriteMessage(websocket.TextMessage, data)
+	err = conn.Send(data)
 	if err != nil {
 		slog.Error(
 			"Failed to send error message.",
-			slog.String("client", conn.RemoteAddr().String()),
+			slog.String("client", conn.RemoteAddr()),
 			slog.String("error", err.Error()),
 		)
 		return
@@ -110,7 +134,7 @@ func SendErrorMessage(player *Player, errorMsg ErrorResponseMessage) {
 		return
 	}
 
-	err = player.conn.WriteMessage(websocket.TextMessage, data)
+	err = player.conn.Send(data)
 	if err != nil {
 		slog.Error(
 			"Failed to send error message.",
@@ -146,7 +170,7 @@ func BroadcastMessage(players map[string]*Player, msg MessageBase, excluded *str
 		return fmt.Errorf("failed to get schema storage: %w", err)
 	}
 
-	err = ss.validate(msg.GetType(), data)
+	err = ss.validate(GetSchemaId(msg), data)
 	if err != nil {
 		slog.Error(
 			"Failed to validate outgoing message.",
@@ -160,7 +184,11 @@ func BroadcastMessage(players map[string]*Player, msg MessageBase, excluded *str
 		if excluded != nil && player.id == *excluded {
 			continue
 		}
-		err = player.conn.WriteMessage(websocket.TextMessage, data)
+		// players restored from a snapshot have no connection until they reconnect
+		if player.conn == nil {
+			continue
+		}
+		err = player.conn.Send(data)
 		if err != nil {
 			slog.Warn(
 				"Failed to send message",
@@ -175,3 +203,33 @@ func BroadcastMessage(players map[string]*Player, msg MessageBase, excluded *str
 
 	return nil
 }
+
+// BroadcastMessage sends the message to players and spectators and records it in the game log.
+func (g *Game) BroadcastMessage(players map[string]*Player, msg MessageBase, excluded *string) error {
+	g.history.Record(OutboundEvent, msg)
+	g.broadcastToSpectators(msg, excluded)
+	return BroadcastMessage(players, msg, excluded)
+}
+
+// BroadcastWordsUnlocked sends words to the hint giver and opposing teams, and a redacted copy to guessers.
+func (g *Game) BroadcastWordsUnlocked(players map[string]*Player, msg RedactableMessage) error {
+	g.history.Record(OutboundEvent, msg)
+	g.broadcastToSpectators(msg, nil)
+
+	allowed := make(map[string]*Player, len(players))
+	guessers := make(map[string]*Player, len(players))
+	for id, player := range players {
+		if g.CanSeeWordsUnlocked(player) {
+			allowed[id] = player
+		} else {
+			guessers[id] = player
+		}
+	}
+	if err := BroadcastMessage(allowed, msg, nil); err != nil {
+		return err
+	}
+	if len(guessers) == 0 {
+		return nil
+	}
+	return BroadcastMessage(guessers, msg.Redact(), nil)
+}
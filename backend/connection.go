@@ -0,0 +1,234 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Number of outgoing messages queued per client before the slow client policy applies
+const SendQueueSize = 64
+
+// Longest time a single write to a client may take
+const WriteTimeout = 10 * time.Second
+
+// Default time between heartbeat pings
+const DefaultPingInterval = 30 * time.Second
+
+// Time between heartbeat pings, a client that answers none of two consecutive pings is disconnected
+var pingInterval = DefaultPingInterval
+
+type SlowClientPolicy string
+
+const (
+	// Drop messages that do not fit into the full queue
+	DropMessages SlowClientPolicy = "drop"
+	// Close the connection of a client whose queue is full, the player can reconnect afterwards
+	DisconnectClient SlowClientPolicy = "disconnect"
+)
+
+var slowClientPolicy = DisconnectClient
+
+var (
+	// Connection mutex
+	connMtx sync.Mutex
+	// All open client connections, closed together on shutdown
+	openConnections = make(map[*Connection]struct{})
+	// Running writer goroutines
+	writers sync.WaitGroup
+)
+
+var (
+	ErrSendQueueFull    = errors.New("send queue is full")
+	ErrConnectionClosed = errors.New("connection is closed")
+)
+
+// Connection wraps a client websocket, all writes go through a queue drained by a single writer goroutine.
+type Connection struct {
+	ws *websocket.Conn
+	// Outgoing messages
+	send chan []byte
+	// Closed once the connection shuts down
+	done      chan struct{}
+	closeOnce sync.Once
+	// Close frame sent after the queued messages, nil to close the socket without one
+	closeFrame []byte
+}
+
+func CreateConnection(ws *websocket.Conn) *Connection {
+	c := &Connection{
+		ws:   ws,
+		send: make(chan []byte, SendQueueSize),
+		done: make(chan struct{}),
+	}
+	// every pong pushes the read deadline further, a silent client makes the read fail
+	ws.SetReadDeadline(time.Now().Add(pongTimeout()))
+	ws.SetPongHandler(func(string) error {
+		return ws.SetReadDeadline(time.Now().Add(pongTimeout()))
+	})
+	connMtx.Lock()
+	openConnections[c] = struct{}{}
+	connMtx.Unlock()
+	writers.Add(1)
+	go c.writeLoop()
+	return c
+}
+
+// pongTimeout is how long a client may stay silent before the connection is considered dead.
+func pongTimeout() time.Duration {
+	return 2 * pingInterval
+}
+
+func (c *Connection) RemoteAddr() string {
+	return c.ws.RemoteAddr().String()
+}
+
+// RemoteHost returns the client address without the port.
+func (c *Connection) RemoteHost() string {
+	addr := c.RemoteAddr()
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
+
+func (c *Connection) ReadMessage() (int, []byte, error) {
+	mtype, data, err := c.ws.ReadMessage()
+	if err == nil {
+		// any message proves the client is alive just like a pong
+		err = c.ws.SetReadDeadline(time.Now().Add(pongTimeout()))
+	}
+	return mtype, data, err
+}
+
+// Send queues the message without blocking, a full queue is handled by the slow client policy.
+func (c *Connection) Send(data []byte) error {
+	select {
+	case <-c.done:
+		return ErrConnectionClosed
+	default:
+	}
+
+	select {
+	case c.send <- data:
+		return nil
+	default:
+	}
+
+	if slowClientPolicy == DisconnectClient {
+		slog.Warn("Disconnecting slow client.", "client", c.RemoteAddr())
+		c.abort()
+	}
+	return ErrSendQueueFull
+}
+
+// SendContext queues the message, waiting for space in the queue instead of applying the slow client policy.
+func (c *Connection) SendContext(ctx context.Context, data []byte) error {
+	select {
+	case c.send <- data:
+		return nil
+	case <-c.done:
+		return ErrConnectionClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops accepting messages, the writer sends what is already queued and closes the socket.
+func (c *Connection) Close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+	})
+}
+
+// CloseWithFrame stops accepting messages, the writer sends what is already queued followed by a close frame.
+func (c *Connection) CloseWithFrame(code int, text string) {
+	c.closeOnce.Do(func() {
+		c.closeFrame = websocket.FormatCloseMessage(code, text)
+		close(c.done)
+	})
+}
+
+// abort closes the socket right away, dropping queued messages.
+func (c *Connection) abort() {
+	c.Close()
+	c.ws.Close()
+}
+
+func (c *Connection) writeLoop() {
+	ticker := time.NewTicker(pingInterval)
+	defer func() {
+		ticker.Stop()
+		c.abort()
+		connMtx.Lock()
+		delete(openConnections, c)
+		connMtx.Unlock()
+		writers.Done()
+	}()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := c.ws.WriteControl(websocket.PingMessage, nil, time.Now().Add(WriteTimeout)); err != nil {
+				slog.Warn("Failed to send heartbeat.", "client", c.RemoteAddr(), "err", err)
+				return
+			}
+		case data := <-c.send:
+			if err := c.write(data); err != nil {
+				slog.Warn("Failed to write message.", "client", c.RemoteAddr(), "err", err)
+				return
+			}
+		case <-c.done:
+			// flush messages queued before the connection was closed
+			for {
+				select {
+				case data := <-c.send:
+					if err := c.write(data); err != nil {
+						return
+					}
+				default:
+					if c.closeFrame != nil {
+						c.ws.WriteControl(websocket.CloseMessage, c.closeFrame, time.Now().Add(WriteTimeout))
+					}
+					return
+				}
+			}
+		}
+	}
+}
+
+func (c *Connection) write(data []byte) error {
+	if err := c.ws.SetWriteDeadline(time.Now().Add(WriteTimeout)); err != nil {
+		return err
+	}
+	return c.ws.WriteMessage(websocket.TextMessage, data)
+}
+
+// CloseAllConnections sends the message and a close frame to every client and waits until all
+// queues are flushed or the context expires.
+func CloseAllConnections(ctx context.Context, data []byte, code int, text string) error {
+	connMtx.Lock()
+	for c := range openConnections {
+		c.Send(data)
+		c.CloseWithFrame(code, text)
+	}
+	connMtx.Unlock()
+
+	flushed := make(chan struct{})
+	go func() {
+		writers.Wait()
+		close(flushed)
+	}()
+	select {
+	case <-flushed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
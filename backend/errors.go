@@ -15,6 +15,24 @@ const (
 	ErrRoundNotActive
 	ErrRoundNotPaused
 	ErrGameNotEnded
+	ErrRoomNotFound
+	ErrNotHost
+	ErrInvalidSettings
+	ErrInvalidTeam
+	ErrDeckNotFound
+	ErrInvalidWordList
+	ErrNotOpposingTeam
+	ErrInvalidCredentials
+	ErrGameHistoryNotFound
+	ErrSpectatorAction
+	ErrTargetNotFound
+	ErrTeamsLocked
+	ErrTeamsNotFilled
+	ErrCannotKickSelf
+	ErrPauseLimitReached
+	ErrTooManyAttempts
+	ErrTooManyRooms
+	ErrRoomLimitReached
 )
 
 func GetErrMessage(code ErrorCode) string {
@@ -34,7 +52,7 @@ func GetErrMessage(code ErrorCode) string {
 	case ErrGameNotStarted:
 		return "Game has not started yet."
 	case ErrNotHintGiver:
-		return "Only hint giver can start a round."
+		return "Only the hint giver can perform this action."
 	case ErrNotAllConnected:
 		return "Not all players are connected."
 	case ErrRoundNotActive:
@@ -43,6 +61,42 @@ func GetErrMessage(code ErrorCode) string {
 		return "Round is not paused."
 	case ErrGameNotEnded:
 		return "Game has not ended yet."
+	case ErrRoomNotFound:
+		return "Room does not exist."
+	case ErrNotHost:
+		return "Only the host can perform this action."
+	case ErrInvalidSettings:
+		return "Settings are not valid for the current players."
+	case ErrInvalidTeam:
+		return "Team is not playing in this game."
+	case ErrDeckNotFound:
+		return "Word deck does not exist."
+	case ErrInvalidWordList:
+		return "Word list is not valid."
+	case ErrNotOpposingTeam:
+		return "Only players of an opposing team can do this."
+	case ErrInvalidCredentials:
+		return "Profile name is taken or the secret is wrong."
+	case ErrGameHistoryNotFound:
+		return "Game history does not exist."
+	case ErrSpectatorAction:
+		return "Spectators cannot perform game actions."
+	case ErrTargetNotFound:
+		return "Target player is not in the room."
+	case ErrTeamsLocked:
+		return "Teams are locked by the host."
+	case ErrTeamsNotFilled:
+		return "Not every team has enough players."
+	case ErrCannotKickSelf:
+		return "Host cannot kick themselves."
+	case ErrPauseLimitReached:
+		return "No pauses left in this game."
+	case ErrTooManyAttempts:
+		return "Too many failed attempts, try again later."
+	case ErrTooManyRooms:
+		return "You have too many open rooms."
+	case ErrRoomLimitReached:
+		return "No more rooms can be opened right now, try again later."
 	default:
 		return "Unknown error."
 	}
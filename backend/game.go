@@ -7,17 +7,10 @@ import (
 	"maps"
 	"sync"
 	"time"
-
-	"github.com/gorilla/websocket"
 )
 
 type GameState int
 
-const RoundDuration = 60
-const BatchSize = 10
-const MaxRounds = 4
-const MaxPlayers = 4
-
 const (
 	InLobby GameState = iota
 	InProgress
@@ -27,16 +20,30 @@ const (
 )
 
 type Game struct {
+	// Room code identifying the game
+	code string
+	// Game rules configured by the host
+	settings GameSettings
+	// ID of the player hosting the room
+	hostId string
 	// Is the game currently running
 	gameState GameState
 	// Player mutex
 	playerMtx sync.RWMutex
 	// Connected players
 	players map[string]*Player
+	// Spectator mutex, never held while acquiring the player mutex
+	spectatorMtx sync.RWMutex
+	// Connected spectators, not counted against the player limit
+	spectators map[string]*Player
 	// Player IDs per team
 	teamPlayers map[Team][]string
 	// Team scores
 	teamScores map[Team]int
+	// Number of rounds each player has been the hint giver
+	hintsGiven map[string]uint
+	// Players who joined after the game started, in order of joining
+	joinQueue []QueuedPlayer
 	// Channel for incoming player messages
 	messages chan MessageBase
 	// IDs of words to be used in game
@@ -51,29 +58,141 @@ type Game struct {
 	roundNumber uint
 	// Current round information
 	currentRound *Round
+	// Summaries of finished rounds
+	roundSummaries []RoundSummary
+	// Number of manual pauses in the current game
+	pauseCount int
+	// Was the round paused by a disconnect, only such pauses follow the pause policy
+	autoPaused bool
+	// Action of the running pause countdown, empty if none is running
+	pauseAction PauseAction
+	// Cancels the running pause countdown
+	pauseCancel context.CancelFunc
 	// Round cancel context
 	roundCtx context.Context
 	// Round cancel function
 	roundCancel context.CancelFunc
+	// Time of the last player join or leave
+	lastActivity time.Time
+	// Has any player joined the room, rooms nobody joined are removed sooner
+	hadPlayers bool
+	// Is the room closed and no longer accepting players
+	closed bool
+	// Channel closed to stop the game loop
+	done chan struct{}
+	// Storage for game snapshots, nil if persistence is disabled
+	snapshots *SnapshotStorage
+	// Persist mutex, keeps snapshots built and saved in order so an older snapshot never replaces a newer one
+	persistMtx sync.Mutex
+	// Storage for player profiles, nil if profiles are disabled
+	profiles *ProfileStorage
+	// Event log of the current game, nil if history is disabled
+	history *GameLog
 }
 
-func CreateGame() *Game {
+func CreateGame(code string) *Game {
+	settings := DefaultGameSettings()
 	return &Game{
+		code:             code,
+		settings:         settings,
+		hostId:           "",
 		gameState:        InLobby,
 		playerMtx:        sync.RWMutex{},
 		players:          make(map[string]*Player, 4),
+		spectatorMtx:     sync.RWMutex{},
+		spectators:       make(map[string]*Player),
 		teamPlayers:      make(map[Team][]string),
 		teamScores:       make(map[Team]int),
+		hintsGiven:       make(map[string]uint),
+		joinQueue:        []QueuedPlayer{},
 		messages:         make(chan MessageBase),
-		wordIds:          wordStorage.GetShuffledIds(),
+		wordIds:          wordStorage.GetShuffledIds(settings.Decks),
 		batchedWordCount: 0,
 		wordQueue:        []uint{},
 		currentWordIdx:   0,
 		roundNumber:      0,
 		currentRound:     nil,
+		roundSummaries:   []RoundSummary{},
+		pauseCount:       0,
+		autoPaused:       false,
+		pauseAction:      "",
+		pauseCancel:      nil,
 		roundCtx:         nil,
 		roundCancel:      nil,
+		lastActivity:     time.Now(),
+		hadPlayers:       false,
+		closed:           false,
+		done:             make(chan struct{}),
+		snapshots:        nil,
+		persistMtx:       sync.Mutex{},
+		profiles:         nil,
+		history:          nil,
+	}
+}
+
+func (g *Game) HasPlayer(playerId string) bool {
+	g.playerMtx.RLock()
+	defer g.playerMtx.RUnlock()
+	_, exists := g.players[playerId]
+	return exists
+}
+
+func (g *Game) CreateRoomInfo() RoomInfo {
+	g.playerMtx.RLock()
+	defer g.playerMtx.RUnlock()
+	return RoomInfo{
+		RoomCode:    g.code,
+		PlayerCount: len(g.players),
+		MaxPlayers:  g.settings.MaxPlayers,
+		State:       g.gameState,
+	}
+}
+
+// CloseIfIdle stops the game loop if nobody has been connected to the room for longer than timeout,
+// or RoomUnusedTimeout if nobody ever joined.
+func (g *Game) CloseIfIdle(timeout time.Duration) bool {
+	g.playerMtx.Lock()
+	defer g.playerMtx.Unlock()
+
+	if g.closed {
+		return true
+	}
+	if !g.hadPlayers {
+		timeout = min(timeout, RoomUnusedTimeout)
+	}
+	if !g.AllDisconnected() || time.Since(g.lastActivity) < timeout {
+		return false
 	}
+	g.CancelEndRoundTimer()
+	g.history.Close()
+	g.closed = true
+	close(g.done)
+	return true
+}
+
+// Shutdown pauses an active round, saves the game and stops the game loop before the server exits.
+func (g *Game) Shutdown() {
+	g.playerMtx.Lock()
+	if g.closed {
+		g.playerMtx.Unlock()
+		return
+	}
+
+	var pausedMsg *RoundPausedMessage
+	if g.gameState == InRound {
+		pausedMsg = g.pauseRoundUnlocked()
+	}
+	g.CancelPauseTimer()
+	g.closed = true
+	close(g.done)
+	players := g.GetPlayersCopyUnlocked()
+	g.playerMtx.Unlock()
+
+	if pausedMsg != nil {
+		g.BroadcastMessage(players, pausedMsg, nil)
+	}
+	g.persist()
+	g.history.Close()
 }
 
 func (g *Game) AllConnected() bool {
@@ -94,6 +213,31 @@ func (g *Game) AllDisconnected() bool {
 	return true
 }
 
+// reassignHost hands the host role to another connected player if the host has left.
+func (g *Game) reassignHost() bool {
+	if _, exists := g.players[g.hostId]; exists {
+		return false
+	}
+	g.hostId = ""
+	for id, player := range g.players {
+		if player.connected {
+			g.hostId = id
+			break
+		}
+	}
+	return g.hostId != ""
+}
+
+// AllTeamsFilled reports whether every playing team has enough members to play a round.
+func (g *Game) AllTeamsFilled() bool {
+	for _, info := range GetTeamInfos(g.settings.TeamCount) {
+		if len(g.teamPlayers[info.Id]) < MinTeamMembers {
+			return false
+		}
+	}
+	return true
+}
+
 func (g *Game) CancelEndRoundTimer() {
 	if g.roundCancel != nil {
 		g.roundCancel()
@@ -103,21 +247,29 @@ func (g *Game) CancelEndRoundTimer() {
 
 func (g *Game) reset(withPlayers bool) {
 	g.CancelEndRoundTimer()
+	g.CancelPauseTimer()
 	g.gameState = InLobby
-	g.teamPlayers[Red] = []string{}
-	g.teamPlayers[Blue] = []string{}
-	g.teamScores[Red] = 0
-	g.teamScores[Blue] = 0
-	g.wordIds = wordStorage.GetShuffledIds()
+	clear(g.teamPlayers)
+	clear(g.teamScores)
+	clear(g.hintsGiven)
+	g.joinQueue = []QueuedPlayer{}
+	if withPlayers {
+		g.settings = DefaultGameSettings()
+	}
+	g.wordIds = wordStorage.GetShuffledIds(g.settings.Decks)
 	g.batchedWordCount = 0
 	g.wordQueue = []uint{}
 	g.currentWordIdx = 0
 	g.roundNumber = 0
 	g.currentRound = nil
+	g.roundSummaries = []RoundSummary{}
+	g.pauseCount = 0
+	g.history.Restart()
 	if withPlayers {
 		for k := range g.players {
 			delete(g.players, k)
 		}
+		g.hostId = ""
 	} else {
 		for k, player := range g.players {
 			if !player.connected {
@@ -127,28 +279,60 @@ func (g *Game) reset(withPlayers bool) {
 			player.SetReady(false)
 			player.SetTeam(Unassigned)
 		}
+		g.reassignHost()
 	}
 }
 
 func (g *Game) run() {
 	var err error
 	for {
-		message := <-g.messages
+		var message MessageBase
+		select {
+		case message = <-g.messages:
+		case <-g.done:
+			slog.Debug("Game loop stopped.", "roomCode", g.code)
+			return
+		}
+		g.history.Record(InboundEvent, message)
 		switch message := message.(type) {
 		case *ChangeTeamMessage:
 			err = g.changePlayerTeam(message.PlayerId, message.Team)
+		case *UpdateSettingsMessage:
+			err = g.updateSettings(message.PlayerId, message.Settings)
+		case *DeckListMessage:
+			err = g.sendDeckList(message.PlayerId)
+		case *UploadWordsMessage:
+			err = g.uploadWords(message.PlayerId, message.Title, message.Format, message.Data)
 		case *PlayerReadyMessage:
 			var allReady bool
 			allReady, err = g.changePlayerReadyStatus(message.PlayerId, message.IsReady)
 			if allReady {
 				g.prepareRound()
 			}
+		case *KickPlayerMessage:
+			err = g.kickPlayer(message.PlayerId, message.TargetId)
+		case *MovePlayerMessage:
+			err = g.movePlayer(message.PlayerId, message.TargetId, message.Team)
+		case *TransferHostMessage:
+			err = g.transferHost(message.PlayerId, message.TargetId)
+		case *ShuffleTeamsMessage:
+			err = g.shuffleTeams(message.PlayerId, message.Mode)
+		case *StartGameMessage:
+			var start bool
+			start, err = g.startGame(message.PlayerId)
+			if start {
+				g.prepareRound()
+			}
 		case *StartRoundMessage:
 			g.startRound(message.PlayerId)
 		case *SkipWordMessage:
 			err = g.skipCurrentWord(message.PlayerId)
 		case *GuessWordMessage:
 			err = g.guessWord(message.PlayerId)
+		case *BuzzMessage:
+			err = g.buzzWord(message.PlayerId, message.WordId)
+		case *PauseRoundMessage:
+			err = g.pauseRound(message.PlayerId)
 		case *ResumeRoundMessage:
 			g.resumeRound(message.PlayerId)
 		case *ResetGameMessage:
@@ -160,13 +344,26 @@ func (g *Game) run() {
 			slog.Error("Failed to process message", "type", message.GetType(), "err", err)
 			err = nil
 		}
+		g.persist()
 	}
 }
 
-func (g *Game) AddPlayer(conn *websocket.Conn, name string) (string, error) {
+func (g *Game) AddPlayer(conn *Connection, name string, profileId string) (string, error) {
 	g.playerMtx.Lock()
 
-	if len(g.players) == 4 {
+	if g.closed {
+		SendDirectErrorMessage(
+			conn,
+			*CreateErrorMessage(
+				ConnectMsg,
+				ErrRoomNotFound,
+			),
+		)
+		g.playerMtx.Unlock()
+		return "", fmt.Errorf("player from %s cannot connect, room %s is closed", conn.RemoteAddr(), g.code)
+	}
+
+	if len(g.players) >= g.settings.MaxPlayers {
 		SendDirectErrorMessage(
 			conn,
 			*CreateErrorMessage(
@@ -175,7 +372,7 @@ func (g *Game) AddPlayer(conn *websocket.Conn, name string) (string, error) {
 			),
 		)
 		g.playerMtx.Unlock()
-		return "", fmt.Errorf("player from %s cannot connect, game is full", conn.RemoteAddr().String())
+		return "", fmt.Errorf("player from %s cannot connect, game is full", conn.RemoteAddr())
 	}
 
 	newId := generateUUID()
@@ -188,8 +385,18 @@ func (g *Game) AddPlayer(conn *websocket.Conn, name string) (string, error) {
 		isReady:      false,
 		team:         -1,
 		connected:    true,
+		profileId:    profileId,
 	}
 	g.players[newId] = player
+	g.lastActivity = time.Now()
+	g.hadPlayers = true
+	if g.hostId == "" {
+		g.hostId = newId
+	}
+	if g.AcceptsLateJoiners() {
+		// the game is running, the player gets a team at the next round boundary
+		g.joinQueue = append(g.joinQueue, QueuedPlayer{PlayerId: newId, Team: Unassigned})
+	}
 
 	// get copy of players to unlock early to not block other operations while sending messages
 	players := g.GetPlayersCopyUnlocked()
@@ -204,6 +411,7 @@ func (g *Game) AddPlayer(conn *websocket.Conn, name string) (string, error) {
 		},
 		SessionToken: player.sessionToken,
 		Name:         name,
+		RoomCode:     g.code,
 	}
 	// send connect ack to the new player
 	if err := SendUnicastMessage(player, connectMsg); err != nil {
@@ -217,12 +425,7 @@ func (g *Game) AddPlayer(conn *websocket.Conn, name string) (string, error) {
 	g.playerMtx.Unlock()
 
 	// create a player list message to send lobby state to player
-	listMsg := &PlayerListMessage{
-		TypeProperty: TypeProperty{
-			Type: PlayerListMsg,
-		},
-		Players: g.CreatePlayerList(),
-	}
+	listMsg := g.CreatePlayerListMessage()
 	// send player list to the new player
 	if err := SendUnicastMessage(player, listMsg); err != nil {
 		slog.Warn(
@@ -235,15 +438,35 @@ func (g *Game) AddPlayer(conn *websocket.Conn, name string) (string, error) {
 	// create a player joined message to notify other players
 	joinedMsg := player.CreatePlayerJoinedMessage()
 	// broadcast player joined message to all other players, excluding the new player
-	BroadcastMessage(players, joinedMsg, &player.id)
+	g.BroadcastMessage(players, joinedMsg, &player.id)
+
+	g.playerMtx.RLock()
+	if g.AcceptsLateJoiners() {
+		if err := g.sendLateJoinStateUnlocked(player); err != nil {
+			slog.Warn("Failed to send late join state.", "playerId", player.id, "err", err)
+		}
+	}
+	g.playerMtx.RUnlock()
 
 	return player.id, nil
 }
 
-func (g *Game) ReconnectPlayer(conn *websocket.Conn, playerId string, sessionToken string) error {
+func (g *Game) ReconnectPlayer(conn *Connection, playerId string, sessionToken string) error {
 	g.playerMtx.Lock()
 
-	if len(g.players) == MaxPlayers && g.AllConnected() {
+	if g.closed {
+		SendDirectErrorMessage(
+			conn,
+			*CreateErrorMessage(
+				ConnectMsg,
+				ErrRoomNotFound,
+			),
+		)
+		g.playerMtx.Unlock()
+		return fmt.Errorf("player from %s cannot reconnect, room %s is closed", conn.RemoteAddr(), g.code)
+	}
+
+	if len(g.players) >= g.settings.MaxPlayers && g.AllConnected() {
 		// full lobby, everyone connected
 		SendDirectErrorMessage(
 			conn,
@@ -253,7 +476,7 @@ func (g *Game) ReconnectPlayer(conn *websocket.Conn, playerId string, sessionTok
 			),
 		)
 		g.playerMtx.Unlock()
-		return fmt.Errorf("player from %s cannot connect, game is full", conn.RemoteAddr().String())
+		return fmt.Errorf("player from %s cannot connect, game is full", conn.RemoteAddr())
 	}
 
 	player, exist := g.players[playerId]
@@ -277,6 +500,7 @@ func (g *Game) ReconnectPlayer(conn *websocket.Conn, playerId string, sessionTok
 
 	player.SetConnection(conn)
 	player.SetConnected(true)
+	g.lastActivity = time.Now()
 	words := g.PreparePendingWordBatch()
 
 	// create a reconnect message for returning player
@@ -290,19 +514,25 @@ func (g *Game) ReconnectPlayer(conn *websocket.Conn, playerId string, sessionTok
 			},
 			SessionToken: player.sessionToken,
 			Name:         player.name,
+			RoomCode:     g.code,
 		},
 		Team:              player.team,
 		State:             g.gameState,
-		RemainingDuration: g.currentRound.Duration,
+		Settings:          g.settings,
+		RemainingDuration: g.currentRound.CalculateRoundPausedDuration(),
+		TimingProperty:    g.currentRound.CreateTimingProperty(),
 		CurrentTeam:       g.currentRound.Team,
-		GuesserId:         g.currentRound.GuesserId,
+		GuesserIds:        g.currentRound.GuesserIds,
 		HintGiverId:       g.currentRound.HintGiverId,
-		RedScore:          g.teamScores[Red],
-		BlueScore:         g.teamScores[Blue],
+		Scores:            g.CreateScoreMap(),
 		Words:             words,
 	}
+	var ackMsg MessageBase = reconnectMsg
+	if g.currentRound != nil && !g.CanSeeWordsUnlocked(player) {
+		ackMsg = reconnectMsg.Redact()
+	}
 	// send reconnect ack to returning player
-	if err := SendUnicastMessage(player, reconnectMsg); err != nil {
+	if err := SendUnicastMessage(player, ackMsg); err != nil {
 		slog.Warn(
 			"Failed to send reconnect ack message.",
 			slog.String("playerId", player.id),
@@ -311,15 +541,11 @@ func (g *Game) ReconnectPlayer(conn *websocket.Conn, playerId string, sessionTok
 	}
 
 	players := g.GetPlayersCopyUnlocked()
+	countdownMsg := g.schedulePausePolicyUnlocked()
 	g.playerMtx.Unlock()
 
 	// create a player list message to send lobby state to player
-	listMsg := &PlayerListMessage{
-		TypeProperty: TypeProperty{
-			Type: PlayerListMsg,
-		},
-		Players: g.CreatePlayerList(),
-	}
+	listMsg := g.CreatePlayerListMessage()
 	// send player list to the new player
 	if err := SendUnicastMessage(player, listMsg); err != nil {
 		slog.Warn(
@@ -332,7 +558,10 @@ func (g *Game) ReconnectPlayer(conn *websocket.Conn, playerId string, sessionTok
 	// create a player reconnected message to notify other players
 	reconnectedMsg := player.CreatePlayerReconnectedMessage()
 	// broadcast player reconnected message to all other players, excluding the reconnected player
-	BroadcastMessage(players, reconnectedMsg, &player.id)
+	g.BroadcastMessage(players, reconnectedMsg, &player.id)
+	if countdownMsg != nil {
+		g.BroadcastMessage(players, countdownMsg, nil)
+	}
 
 	return nil
 }
@@ -351,8 +580,15 @@ func (g *Game) RemovePlayer(playerId string) {
 		g.playerMtx.Unlock()
 		return
 	}
+	if g.closed {
+		// the room was shut down with the server, keep the saved state for the restart
+		g.playerMtx.Unlock()
+		return
+	}
 
-	if g.gameState == InLobby {
+	g.lastActivity = time.Now()
+	if g.gameState == InLobby || g.dequeuePlayerUnlocked(playerId) {
+		// queued players have no part in the game yet and leave like in the lobby
 		delete(g.players, playerId)
 	} else {
 		player.SetConnected(false)
@@ -363,6 +599,7 @@ func (g *Game) RemovePlayer(playerId string) {
 		slog.Info("All players have left. Resetting the game.")
 		g.reset(true)
 		g.playerMtx.Unlock()
+		g.persist()
 		return
 	}
 
@@ -375,6 +612,8 @@ func (g *Game) RemovePlayer(playerId string) {
 			}
 		}
 	}
+	hostChanged := !disconnected && g.reassignHost()
+	hostId := g.hostId
 
 	// get copy of players to unlock early
 	players := g.GetPlayersCopyUnlocked()
@@ -385,24 +624,40 @@ func (g *Game) RemovePlayer(playerId string) {
 		disconnectedMsg := player.CreatePlayerDisconnectedMessage()
 		// broadcast player left message to all other players
 		// do not need to exclude player, since connection has been closed
-		BroadcastMessage(players, disconnectedMsg, &playerId)
+		g.BroadcastMessage(players, disconnectedMsg, &playerId)
 
+		g.playerMtx.Lock()
 		if g.gameState == InRound {
 			// player disconnected during an active round, pausse round
-			g.gameState = Paused
-			// cancel end round timer
-			g.CancelEndRoundTimer()
-			// update round duration
-			g.currentRound.SetDuration(
-				g.currentRound.CalculateRoundPausedDuration(),
-			)
-			// create round paused message
-			pausedMsg := g.currentRound.CreateRoundPausedMessage()
-			BroadcastMessage(players, pausedMsg, &playerId)
+			pausedMsg := g.pauseRoundUnlocked()
+			g.autoPaused = true
+			g.BroadcastMessage(players, pausedMsg, &playerId)
+		}
+		countdownMsg := g.schedulePausePolicyUnlocked()
+		if countdownMsg != nil {
+			g.BroadcastMessage(players, countdownMsg, &playerId)
+		}
+		paused := g.gameState == Paused
+		g.playerMtx.Unlock()
+		if paused {
+			g.persist()
 		}
 	} else {
 		leftMsg := player.CreatePlayerLeftMessage()
-		BroadcastMessage(players, leftMsg, nil)
+		g.BroadcastMessage(players, leftMsg, nil)
+
+		if hostChanged {
+			// host left the lobby, notify players about the new host
+			hostChangedMsg := &HostChangedMessage{
+				TypeProperty: TypeProperty{
+					Type: HostChangedMsg,
+				},
+				PlayerIdProperty: PlayerIdProperty{
+					PlayerId: hostId,
+				},
+			}
+			g.BroadcastMessage(players, hostChangedMsg, nil)
+		}
 	}
 }
 
@@ -417,52 +672,84 @@ func (g *Game) changePlayerTeam(playerId string, team Team) error {
 		return fmt.Errorf("player with ID %s not found", playerId)
 	}
 
-	if g.gameState != InLobby {
+	if g.settings.TeamsLocked && playerId != g.hostId {
 		SendErrorMessage(
 			player,
 			*CreateErrorMessage(
 				ChangeTeamMsg,
+				ErrTeamsLocked,
+			),
+		)
+		return fmt.Errorf("teams are locked, cannot change team")
+	}
+
+	return g.assignTeamUnlocked(player, player, team, ChangeTeamMsg)
+}
+
+// assignTeamUnlocked moves the target player to the team, errors are reported to the requesting player.
+func (g *Game) assignTeamUnlocked(requester *Player, target *Player, team Team, msgType MessageType) error {
+	if g.gameState != InLobby && g.queueIndexUnlocked(target.id) >= 0 {
+		// players who joined a running game pick the team they join at the next round boundary
+		return g.queueTeamUnlocked(requester, target, team, msgType)
+	}
+
+	if g.gameState != InLobby {
+		SendErrorMessage(
+			requester,
+			*CreateErrorMessage(
+				msgType,
 				ErrGameNotInLobby,
 			),
 		)
 		return fmt.Errorf("game not in lobby state, cannot change team")
 	}
 
-	if len(g.teamPlayers[team]) >= MaxTeamMembers {
+	if team != Unassigned && !team.IsPlaying(g.settings.TeamCount) {
 		SendErrorMessage(
-			player,
+			requester,
 			*CreateErrorMessage(
-				ChangeTeamMsg,
+				msgType,
+				ErrInvalidTeam,
+			),
+		)
+		return nil
+	}
+
+	if len(g.teamPlayers[team]) >= g.settings.MaxTeamMembers {
+		SendErrorMessage(
+			requester,
+			*CreateErrorMessage(
+				msgType,
 				ErrTeamFull,
 			),
 		)
 		return nil
 	}
 
-	oldTeam := player.team
+	oldTeam := target.team
 	// old team is the same as team to assign, ignore
 	if oldTeam == team {
 		return nil
 	}
 
 	// change team
-	player.SetTeam(team)
+	target.SetTeam(team)
 	if oldTeam == Unassigned {
-		g.teamPlayers[team] = append(g.teamPlayers[team], playerId)
-		slog.Debug("Player assigned to team", "player_id", playerId, "team", team, "teamplayers", g.teamPlayers)
+		g.teamPlayers[team] = append(g.teamPlayers[team], target.id)
+		slog.Debug("Player assigned to team", "player_id", target.id, "team", team, "teamplayers", g.teamPlayers)
 	} else {
 		for i, v := range g.teamPlayers[oldTeam] {
-			if v == playerId {
+			if v == target.id {
 				g.teamPlayers[oldTeam] = append(g.teamPlayers[oldTeam][:i], g.teamPlayers[oldTeam][i+1:]...)
 				break
 			}
 		}
 		if team != Unassigned {
-			g.teamPlayers[team] = append(g.teamPlayers[team], playerId)
+			g.teamPlayers[team] = append(g.teamPlayers[team], target.id)
 		}
-		slog.Debug("Player changed team", "player_id", playerId, "old_team", oldTeam, "new_team", team, "teamplayers", g.teamPlayers)
+		slog.Debug("Player changed team", "player_id", target.id, "old_team", oldTeam, "new_team", team, "teamplayers", g.teamPlayers)
 	}
-	player.SetReady(false)
+	target.SetReady(false)
 
 	players := g.GetPlayersCopyUnlocked()
 	teamChangedMsg := &TeamChangedMessage{
@@ -470,11 +757,190 @@ func (g *Game) changePlayerTeam(playerId string, team Team) error {
 			Type: TeamChangedMsg,
 		},
 		PlayerIdProperty: PlayerIdProperty{
-			PlayerId: playerId,
+			PlayerId: target.id,
 		},
 		Team: team,
 	}
-	BroadcastMessage(players, teamChangedMsg, nil)
+	g.BroadcastMessage(players, teamChangedMsg, nil)
+	return nil
+}
+
+func (g *Game) updateSettings(playerId string, settings GameSettings) error {
+	// lock before accessing players
+	g.playerMtx.Lock()
+	defer g.playerMtx.Unlock()
+
+	// find player
+	player, exists := g.players[playerId]
+	if !exists {
+		return fmt.Errorf("player with ID %s not found", playerId)
+	}
+
+	if g.gameState != InLobby {
+		SendErrorMessage(
+			player,
+			*CreateErrorMessage(
+				UpdateSettingsMsg,
+				ErrGameNotInLobby,
+			),
+		)
+		return fmt.Errorf("game not in lobby state, cannot update settings")
+	}
+
+	if g.hostId != playerId {
+		SendErrorMessage(
+			player,
+			*CreateErrorMessage(
+				UpdateSettingsMsg,
+				ErrNotHost,
+			),
+		)
+		return fmt.Errorf("only the host can update settings")
+	}
+
+	// settings must accommodate players already in the room
+	valid := settings.MaxPlayers >= len(g.players)
+	// and leave room for enough players to fill every team
+	if settings.TeamCount*MinTeamMembers > settings.MaxPlayers {
+		valid = false
+	}
+	// and give every team the same number of rounds
+	if settings.MaxRounds%uint(settings.TeamCount) != 0 {
+		valid = false
+	}
+	for team, members := range g.teamPlayers {
+		if len(members) > settings.MaxTeamMembers {
+			valid = false
+		}
+		if len(members) > 0 && !team.IsPlaying(settings.TeamCount) {
+			valid = false
+		}
+	}
+	if !valid {
+		SendErrorMessage(
+			player,
+			*CreateErrorMessage(
+				UpdateSettingsMsg,
+				ErrInvalidSettings,
+			),
+		)
+		return fmt.Errorf("settings cannot accommodate current players")
+	}
+
+	for _, deckId := range settings.Decks {
+		if !wordStorage.HasDeck(deckId, g.code) {
+			SendErrorMessage(
+				player,
+				*CreateErrorMessage(
+					UpdateSettingsMsg,
+					ErrDeckNotFound,
+				),
+			)
+			return fmt.Errorf("deck %s does not exist", deckId)
+		}
+	}
+
+	g.settings = settings
+	// draw words from the newly selected decks
+	g.wordIds = wordStorage.GetShuffledIds(settings.Decks)
+	g.batchedWordCount = 0
+
+	players := g.GetPlayersCopyUnlocked()
+	settingsChangedMsg := &SettingsChangedMessage{
+		TypeProperty: TypeProperty{
+			Type: SettingsChangedMsg,
+		},
+		Settings: settings,
+		Teams:    GetTeamInfos(settings.TeamCount),
+	}
+	g.BroadcastMessage(players, settingsChangedMsg, nil)
+	return nil
+}
+
+func (g *Game) sendDeckList(playerId string) error {
+	g.playerMtx.RLock()
+	defer g.playerMtx.RUnlock()
+
+	player, exists := g.players[playerId]
+	if !exists {
+		return fmt.Errorf("player with ID %s not found", playerId)
+	}
+
+	deckListMsg := &DeckListMessage{
+		TypeProperty: TypeProperty{
+			Type: DeckListMsg,
+		},
+		PlayerIdProperty: PlayerIdProperty{
+			PlayerId: playerId,
+		},
+		Decks: wordStorage.GetDeckInfos(g.code),
+	}
+	return SendUnicastMessage(player, deckListMsg)
+}
+
+func (g *Game) uploadWords(playerId string, title string, format WordListFormat, data string) error {
+	g.playerMtx.RLock()
+	defer g.playerMtx.RUnlock()
+
+	player, exists := g.players[playerId]
+	if !exists {
+		return fmt.Errorf("player with ID %s not found", playerId)
+	}
+
+	if g.gameState != InLobby {
+		SendErrorMessage(
+			player,
+			*CreateErrorMessage(
+				UploadWordsMsg,
+				ErrGameNotInLobby,
+			),
+		)
+		return fmt.Errorf("game not in lobby state, cannot upload words")
+	}
+
+	ss, err := GetSchemaStorage()
+	if err != nil {
+		return fmt.Errorf("failed to get schema storage: %w", err)
+	}
+
+	words, err := ParseWordList(format, data)
+	if err == nil && (len(words) == 0 || len(words) > MaxUploadedWords) {
+		err = fmt.Errorf("word list must contain between 1 and %d words", MaxUploadedWords)
+	}
+	if err == nil {
+		for i, word := range words {
+			// placeholder ID, real IDs are assigned when the deck is registered
+			word.ID = uint(i + 1)
+			if err = ValidateWord(ss, word); err != nil {
+				break
+			}
+		}
+	}
+	if err != nil {
+		SendErrorMessage(
+			player,
+			*CreateErrorMessage(
+				UploadWordsMsg,
+				ErrInvalidWordList,
+			),
+		)
+		return fmt.Errorf("invalid uploaded word list: %w", err)
+	}
+
+	deck := wordStorage.AddRoomDeck(g.code, title, words)
+	slog.Info("Custom deck uploaded.", "roomCode", g.code, "deck", deck.Id, "words", len(deck.Words))
+
+	players := g.GetPlayersCopyUnlocked()
+	uploadedMsg := &WordsUploadedMessage{
+		TypeProperty: TypeProperty{
+			Type: WordsUploadedMsg,
+		},
+		PlayerIdProperty: PlayerIdProperty{
+			PlayerId: playerId,
+		},
+		Deck: deck.CreateDeckInfo(),
+	}
+	g.BroadcastMessage(players, uploadedMsg, nil)
 	return nil
 }
 
@@ -526,8 +992,8 @@ func (g *Game) changePlayerReadyStatus(playerId string, isReady bool) (bool, err
 	// get copy of players to unlock early
 	players := g.GetPlayersCopyUnlocked()
 	// broadcast ready status change
-	BroadcastMessage(players, msg, nil)
-	allReady := true
+	g.BroadcastMessage(players, msg, nil)
+	allReady := g.AllTeamsFilled()
 	for _, p := range players {
 		if !p.isReady {
 			allReady = false
@@ -541,9 +1007,11 @@ func (g *Game) prepareRound() {
 	g.playerMtx.Lock()
 	defer g.playerMtx.Unlock()
 
+	g.slotQueuedPlayersUnlocked()
+
 	// select team and players for round
-	team := selectTeam(g.roundNumber)
-	if len(g.teamPlayers[team]) < 2 {
+	team := selectTeam(g.roundNumber, g.settings.TeamCount)
+	if len(g.teamPlayers[team]) < MinTeamMembers {
 		slog.Error("Not enough players to start the round")
 		return
 	}
@@ -551,26 +1019,30 @@ func (g *Game) prepareRound() {
 	// pick words and broadcast to players
 	words := g.PrepareNextWordBatch()
 
-	hintGiverId, guesserId := g.selectTeamPlayers(team, g.roundNumber)
+	hintGiverId, guesserIds := g.selectTeamPlayers(team)
 	// create round object
 	g.currentRound = &Round{
 		Team:        team,
-		GuesserId:   guesserId,
+		GuesserIds:  guesserIds,
 		HintGiverId: hintGiverId,
-		Duration:    RoundDuration,
+		Duration:    g.settings.RoundDuration,
 		Words:       words,
+		// the timer starts once the hint giver starts the round
+		RemainingTime: int64(g.settings.RoundDuration) * 1000,
 	}
 
 	// broadcast round prepare message
 	players := g.GetPlayersCopyUnlocked()
 	roundSetupMsg := g.currentRound.CreateRoundSetupMessage()
-	err := BroadcastMessage(players, roundSetupMsg, nil)
+	err := g.BroadcastWordsUnlocked(players, roundSetupMsg)
 	if err != nil {
 		slog.Error("Failed to broadcast round setup message", "err", err)
 		return
 	}
 
 	g.gameState = InProgress
+	// keep the game log on disk once the game has started
+	g.history.Persist()
 }
 
 func (g *Game) startRound(playerId string) {
@@ -606,24 +1078,76 @@ func (g *Game) startRound(playerId string) {
 
 	players := g.GetPlayersCopyUnlocked()
 	roundStartedMsg := g.currentRound.CreateRoundStartedMessage()
-	err := BroadcastMessage(players, roundStartedMsg, nil)
+	err := g.BroadcastMessage(players, roundStartedMsg, nil)
 	if err != nil {
 		slog.Error("Failed to broadcast round started message", "err", err)
 		return
 	}
 
 	g.gameState = InRound
-	g.currentRound.StartTime = time.Now().UnixMilli()
-	g.roundCtx, g.roundCancel = context.WithCancel(context.Background())
-	go func(ctx context.Context, duration int) {
-		select {
-		case <-time.After(time.Duration(duration) * time.Second):
-			g.endRound()
-		case <-ctx.Done():
-			slog.Info("Start round roundCancel cancelled.")
-			return
-		}
-	}(g.roundCtx, g.currentRound.Duration)
+	g.currentRound.StartWordTimer()
+	g.startRoundTimerUnlocked()
+}
+
+// pauseRoundUnlocked stops the round timer and keeps the remaining time until the round is resumed.
+func (g *Game) pauseRoundUnlocked() *RoundPausedMessage {
+	g.gameState = Paused
+	// cancel end round timer
+	g.CancelEndRoundTimer()
+	// keep the time left in the round
+	g.currentRound.StopTimer()
+	g.currentRound.PauseWordTimer()
+	return g.currentRound.CreateRoundPausedMessage()
+}
+
+func (g *Game) pauseRound(playerId string) error {
+	g.playerMtx.Lock()
+	defer g.playerMtx.Unlock()
+
+	player, exists := g.players[playerId]
+	if !exists {
+		return fmt.Errorf("player with ID %s not found", playerId)
+	}
+
+	if g.gameState != InRound {
+		SendErrorMessage(
+			player,
+			*CreateErrorMessage(
+				PauseRoundMsg,
+				ErrRoundNotActive,
+			),
+		)
+		return fmt.Errorf("round is not active, cannot pause round")
+	}
+
+	if g.currentRound.HintGiverId != playerId && g.hostId != playerId {
+		SendErrorMessage(
+			player,
+			*CreateErrorMessage(
+				PauseRoundMsg,
+				ErrNotHintGiver,
+			),
+		)
+		return fmt.Errorf("only the hint giver or host can pause round")
+	}
+
+	if g.pauseCount >= g.settings.MaxPauses {
+		SendErrorMessage(
+			player,
+			*CreateErrorMessage(
+				PauseRoundMsg,
+				ErrPauseLimitReached,
+			),
+		)
+		return fmt.Errorf("pause limit of %d reached", g.settings.MaxPauses)
+	}
+
+	g.pauseCount++
+	players := g.GetPlayersCopyUnlocked()
+	pausedMsg := g.pauseRoundUnlocked()
+	g.autoPaused = false
+	g.BroadcastMessage(players, pausedMsg, nil)
+	return nil
 }
 
 func (g *Game) resumeRound(playerId string) {
@@ -654,7 +1178,7 @@ func (g *Game) resumeRound(playerId string) {
 		return
 	}
 
-	if g.currentRound.HintGiverId != playerId {
+	if g.currentRound.HintGiverId != playerId && g.hostId != playerId {
 		SendErrorMessage(
 			player,
 			*CreateErrorMessage(
@@ -663,7 +1187,7 @@ func (g *Game) resumeRound(playerId string) {
 			),
 		)
 		slog.Error(
-			"Only the hint giver can pause round.",
+			"Only the hint giver or host can resume round.",
 			slog.String("playerId", playerId),
 			slog.String("hintGiverId", g.currentRound.HintGiverId),
 		)
@@ -685,12 +1209,18 @@ func (g *Game) resumeRound(playerId string) {
 		return
 	}
 
+	g.resumeRoundUnlocked()
+}
+
+// resumeRoundUnlocked restarts the round timer with the time left when the round was paused.
+func (g *Game) resumeRoundUnlocked() {
+	g.CancelPauseTimer()
 	players := g.GetPlayersCopyUnlocked()
 	g.gameState = InRound
-	g.currentRound.StartTime = time.Now().UnixMilli()
+	g.currentRound.StartWordTimer()
 
 	roundResumedMsg := g.currentRound.CreateRoundResumedMessage()
-	err := BroadcastMessage(players, roundResumedMsg, nil)
+	err := g.BroadcastMessage(players, roundResumedMsg, nil)
 	if err != nil {
 		slog.Error(
 			"Failed to broadcast round paused message",
@@ -699,48 +1229,57 @@ func (g *Game) resumeRound(playerId string) {
 		return
 	}
 
-	g.roundCtx, g.roundCancel = context.WithCancel(context.Background())
-	go func(ctx context.Context, duration int) {
-		select {
-		case <-time.After(time.Duration(duration) * time.Second):
-			g.endRound()
-		case <-ctx.Done():
-			slog.Info("Resume round roundCancel cancelled.")
-			return
-		}
-	}(g.roundCtx, g.currentRound.Duration)
+	g.startRoundTimerUnlocked()
 }
 
-func (g *Game) endRound() {
+// endRound ends the round when its timer runs out, unless the timer was cancelled by a pause,
+// resume or reset while waiting for the lock.
+func (g *Game) endRound(ctx context.Context) {
 	g.playerMtx.Lock()
 
+	if ctx.Err() != nil {
+		slog.Debug("Round timer cancelled before ending the round.", "roomCode", g.code)
+		g.playerMtx.Unlock()
+		return
+	}
 	if g.gameState != InRound {
 		slog.Warn("Cannot end round, game not in round state")
 		g.playerMtx.Unlock()
 		return
 	}
+	g.endRoundLocked()
+}
 
+// endRoundLocked finishes the current round, the player mutex must be held and is released.
+func (g *Game) endRoundLocked() {
 	players := g.GetPlayersCopyUnlocked()
-	if g.roundNumber < MaxRounds-1 {
+	summaryMsg := g.currentRound.CreateRoundSummaryMessage(g.roundNumber)
+	g.roundSummaries = append(g.roundSummaries, summaryMsg.RoundSummary)
+	if g.roundNumber < g.settings.MaxRounds-1 {
 		g.gameState = InProgress
 		g.roundNumber++
 		endRoundMsg := g.currentRound.CreateRoundEndedMessage()
-		err := BroadcastMessage(players, endRoundMsg, nil)
+		err := g.BroadcastMessage(players, endRoundMsg, nil)
 		if err != nil {
 			slog.Error("Failed to broadcast round ended message", "err", err)
-			return
 		}
+		g.BroadcastMessage(players, summaryMsg, nil)
 		g.playerMtx.Unlock()
 		g.prepareRound()
+		g.persist()
 	} else {
 		g.gameState = Ended
+		g.BroadcastMessage(players, summaryMsg, nil)
 		endGameMsg := g.CreateGameEndedMessage()
-		err := BroadcastMessage(players, endGameMsg, nil)
+		err := g.BroadcastMessage(players, endGameMsg, nil)
 		if err != nil {
 			slog.Error("Failed to broadcast game ended message", "err", err)
-			return
 		}
+		summaryMsg := g.CreateGameSummaryMessageUnlocked()
+		g.BroadcastMessage(players, summaryMsg, nil)
+		g.recordProfileStatsUnlocked(summaryMsg)
 		g.playerMtx.Unlock()
+		g.persist()
 	}
 }
 
@@ -772,7 +1311,7 @@ func (g *Game) resetToLobby(playerId string) {
 		return
 	}
 
-	if g.currentRound.HintGiverId != playerId {
+	if g.currentRound.HintGiverId != playerId && g.hostId != playerId {
 		SendErrorMessage(
 			player,
 			*CreateErrorMessage(
@@ -781,7 +1320,7 @@ func (g *Game) resetToLobby(playerId string) {
 			),
 		)
 		slog.Error(
-			"Only the hint giver can reset the game.",
+			"Only the hint giver or host can reset the game.",
 			slog.String("playerId", playerId),
 			slog.String("hintGiverId", g.currentRound.HintGiverId),
 		)
@@ -800,7 +1339,7 @@ func (g *Game) resetToLobby(playerId string) {
 		},
 		Players: remainingPlayers,
 	}
-	err := BroadcastMessage(players, resetMsg, nil)
+	err := g.BroadcastMessage(players, resetMsg, nil)
 	if err != nil {
 		slog.Error(
 			"Failed to broadcast round paused message",
@@ -841,7 +1380,10 @@ func (g *Game) guessWord(playerId string) error {
 		return fmt.Errorf("only the hint giver can mark a guess")
 	}
 
-	g.teamScores[player.team]++
+	g.currentRound.Streak++
+	points := g.settings.Scoring.GuessScore(g.currentRound.Streak)
+	g.teamScores[g.currentRound.Team] += points
+	g.currentRound.RecordWordResult(g.currentWordUnlocked(), WordOutcomeGuessed, playerId, points)
 	g.currentWordIdx++
 
 	players := g.GetPlayersCopyUnlocked()
@@ -852,31 +1394,11 @@ func (g *Game) guessWord(playerId string) error {
 		PlayerIdProperty: PlayerIdProperty{
 			PlayerId: playerId,
 		},
-		RedScore:  g.teamScores[Red],
-		BlueScore: g.teamScores[Blue],
+		Scores: g.CreateScoreMap(),
 	}
-	BroadcastMessage(players, guessedMsg, nil)
+	g.BroadcastMessage(players, guessedMsg, nil)
 
-	remaining := len(g.wordQueue) - int(g.currentWordIdx)
-	if remaining <= 5 {
-		// pick words and broadcast to players
-		words := g.PrepareNextWordBatch()
-
-		wordListMsg := &WordListMessage{
-			TypeProperty: TypeProperty{
-				Type: WordListMsg,
-			},
-			Words: words,
-		}
-
-		err := BroadcastMessage(players, wordListMsg, nil)
-		if err != nil {
-			slog.Error("Failed to broadcast word list message", "err", err)
-			return fmt.Errorf("failed to broadcast word list message: %w", err)
-		}
-	}
-
-	return nil
+	return g.sendNextWordBatchIfLow(players)
 }
 
 func (g *Game) skipCurrentWord(playerId string) error {
@@ -911,6 +1433,11 @@ func (g *Game) skipCurrentWord(playerId string) error {
 		return fmt.Errorf("only the hint giver can skip words")
 	}
 
+	g.currentRound.SkipCount++
+	g.currentRound.Streak = 0
+	points := g.settings.Scoring.SkipScore(g.currentRound.SkipCount)
+	g.teamScores[g.currentRound.Team] += points
+	g.currentRound.RecordWordResult(g.currentWordUnlocked(), WordOutcomeSkipped, playerId, points)
 	g.currentWordIdx++
 	players := g.GetPlayersCopyUnlocked()
 	skippedMsg := &WordSkippedMessage{
@@ -920,48 +1447,164 @@ func (g *Game) skipCurrentWord(playerId string) error {
 		PlayerIdProperty: PlayerIdProperty{
 			PlayerId: playerId,
 		},
+		Scores: g.CreateScoreMap(),
+	}
+	g.BroadcastMessage(players, skippedMsg, nil)
+
+	return g.sendNextWordBatchIfLow(players)
+}
+
+func (g *Game) buzzWord(playerId string, wordId uint) error {
+	// lock before accessing players
+	g.playerMtx.Lock()
+	defer g.playerMtx.Unlock()
+
+	player, exist := g.players[playerId]
+	if !exist {
+		return fmt.Errorf("player ID %s not found", playerId)
+	}
+
+	if g.gameState != InRound {
+		SendErrorMessage(
+			player,
+			*CreateErrorMessage(
+				BuzzMsg,
+				ErrRoundNotActive,
+			),
+		)
+		return fmt.Errorf("round is not running, cannot buzz word")
+	}
+
+	if !player.team.IsPlaying(g.settings.TeamCount) || player.team == g.currentRound.Team {
+		SendErrorMessage(
+			player,
+			*CreateErrorMessage(
+				BuzzMsg,
+				ErrNotOpposingTeam,
+			),
+		)
+		return fmt.Errorf("only opposing team players can buzz")
+	}
+
+	if int(g.currentWordIdx) >= len(g.wordQueue) || g.wordQueue[g.currentWordIdx] != wordId {
+		// someone else already buzzed the word or it was guessed or skipped meanwhile
+		slog.Debug("Ignoring buzz for a word that is no longer current.", "playerId", playerId, "wordId", wordId)
+		return nil
+	}
+
+	g.currentRound.Streak = 0
+	points := g.settings.Scoring.BuzzScore()
+	g.teamScores[g.currentRound.Team] += points
+	g.currentRound.RecordWordResult(g.currentWordUnlocked(), WordOutcomeBuzzed, playerId, points)
+	g.currentWordIdx++
+
+	players := g.GetPlayersCopyUnlocked()
+	buzzedMsg := &WordBuzzedMessage{
+		TypeProperty: TypeProperty{
+			Type: WordBuzzedMsg,
+		},
+		PlayerIdProperty: PlayerIdProperty{
+			PlayerId: playerId,
+		},
+		Scores: g.CreateScoreMap(),
 	}
-	BroadcastMessage(players, skippedMsg, nil)
+	g.BroadcastMessage(players, buzzedMsg, nil)
+
+	return g.sendNextWordBatchIfLow(players)
+}
 
+// sendNextWordBatchIfLow broadcasts another batch of words once few words remain in the queue.
+func (g *Game) sendNextWordBatchIfLow(players map[string]*Player) error {
 	remaining := len(g.wordQueue) - int(g.currentWordIdx)
-	if remaining <= 5 {
-		// pick words and broadcast to players
-		words := g.PrepareNextWordBatch()
+	if remaining > 5 {
+		return nil
+	}
 
-		wordListMsg := &WordListMessage{
-			TypeProperty: TypeProperty{
-				Type: WordListMsg,
-			},
-			Words: words,
-		}
+	// pick words and broadcast to players
+	words := g.PrepareNextWordBatch()
 
-		err := BroadcastMessage(players, wordListMsg, nil)
-		if err != nil {
-			slog.Error("Failed to broadcast word list message", "err", err)
-			return fmt.Errorf("failed to broadcast word list message: %w", err)
-		}
+	wordListMsg := &WordListMessage{
+		TypeProperty: TypeProperty{
+			Type: WordListMsg,
+		},
+		Words: words,
 	}
 
+	err := g.BroadcastWordsUnlocked(players, wordListMsg)
+	if err != nil {
+		slog.Error("Failed to broadcast word list message", "err", err)
+		return fmt.Errorf("failed to broadcast word list message: %w", err)
+	}
 	return nil
 }
 
-func selectTeam(round uint) Team {
-	team := Red
-	if round%2 == 1 {
-		team = Blue
+// CanSeeWordsUnlocked reports whether the player may see the words, which only the hint giver and members
+// of the opposing teams may. Guessers, unassigned and queued players only get word IDs.
+func (g *Game) CanSeeWordsUnlocked(player *Player) bool {
+	if g.currentRound == nil {
+		return true
+	}
+	if player.id == g.currentRound.HintGiverId {
+		return true
+	}
+	return player.team != g.currentRound.Team && player.team.IsPlaying(g.settings.TeamCount)
+}
+
+func selectTeam(round uint, teamCount int) Team {
+	return Team(round % uint(teamCount))
+}
+
+// activeTeamPlayersUnlocked returns the connected members of the team, disconnected members sit out
+// until they reconnect. If too few members are connected, all members are returned so the round can
+// still be set up for when the missing players return.
+func (g *Game) activeTeamPlayersUnlocked(team Team) []string {
+	members := g.teamPlayers[team]
+	active := make([]string, 0, len(members))
+	for _, id := range members {
+		if g.isConnectedUnlocked(id) {
+			active = append(active, id)
+		}
 	}
-	return team
+	if len(active) < MinTeamMembers {
+		return members
+	}
+	return active
+}
+
+func (g *Game) isConnectedUnlocked(playerId string) bool {
+	player, exists := g.players[playerId]
+	return exists && player.connected
 }
 
-func (g *Game) selectTeamPlayers(team Team, round uint) (string, string) {
-	if (round/2)%2 == 0 {
-		return g.teamPlayers[team][0], g.teamPlayers[team][1]
+// selectTeamPlayers picks the active team member who gave the fewest hints so far as the hint giver,
+// preferring connected members and earlier members on ties, and returns the remaining active members as guessers.
+func (g *Game) selectTeamPlayers(team Team) (string, []string) {
+	members := g.activeTeamPlayersUnlocked(team)
+	hintGiverIdx := 0
+	for i, id := range members {
+		best := members[hintGiverIdx]
+		connected, bestConnected := g.isConnectedUnlocked(id), g.isConnectedUnlocked(best)
+		if connected && !bestConnected || connected == bestConnected && g.hintsGiven[id] < g.hintsGiven[best] {
+			hintGiverIdx = i
+		}
+	}
+	hintGiverId := members[hintGiverIdx]
+	g.hintsGiven[hintGiverId]++
+
+	guesserIds := make([]string, 0, len(members)-1)
+	for i, id := range members {
+		if i != hintGiverIdx {
+			guesserIds = append(guesserIds, id)
+		}
 	}
-	return g.teamPlayers[team][1], g.teamPlayers[team][0]
+	return hintGiverId, guesserIds
 }
 
 func (g *Game) PrepareNextWordBatch() []*TabooWord {
-	maxWords := wordStorage.GetWordCount()
+	maxWords := uint(len(g.wordIds))
+	if maxWords == 0 {
+		return []*TabooWord{}
+	}
 
 	// slice away guessed or skipped words
 	if g.currentWordIdx > 0 {
@@ -974,7 +1617,7 @@ func (g *Game) PrepareNextWordBatch() []*TabooWord {
 		g.currentWordIdx = 0
 	}
 
-	need := BatchSize - len(g.wordQueue)
+	need := g.settings.BatchSize - len(g.wordQueue)
 	if need <= 0 {
 		return []*TabooWord{}
 	}
@@ -986,7 +1629,7 @@ func (g *Game) PrepareNextWordBatch() []*TabooWord {
 		g.batchedWordCount++
 
 		if pos+1 == maxWords {
-			g.wordIds = wordStorage.GetShuffledIds()
+			g.wordIds = wordStorage.GetShuffledIds(g.settings.Decks)
 		}
 	}
 
@@ -1001,10 +1644,25 @@ func (g *Game) PreparePendingWordBatch() []*TabooWord {
 	return wordStorage.GetWordsByIds(g.wordQueue[g.currentWordIdx:])
 }
 
-func (g *Game) CreatePlayerList() []PlayerInfo {
-	players := g.GetPlayersCopy()
-	playerList := make([]PlayerInfo, 0, len(players))
-	for _, p := range players {
+func (g *Game) CreatePlayerListMessage() *PlayerListMessage {
+	g.playerMtx.RLock()
+	defer g.playerMtx.RUnlock()
+	return &PlayerListMessage{
+		TypeProperty: TypeProperty{
+			Type: PlayerListMsg,
+		},
+		HostId:     g.hostId,
+		Settings:   g.settings,
+		Teams:      GetTeamInfos(g.settings.TeamCount),
+		Players:    g.CreatePlayerListUnlocked(),
+		Spectators: g.CreateSpectatorList(),
+		Queue:      append([]QueuedPlayer{}, g.joinQueue...),
+	}
+}
+
+func (g *Game) CreatePlayerListUnlocked() []PlayerInfo {
+	playerList := make([]PlayerInfo, 0, len(g.players))
+	for _, p := range g.players {
 		playerList = append(playerList, PlayerInfo{
 			Id:        p.id,
 			Name:      p.name,
@@ -1016,6 +1674,14 @@ func (g *Game) CreatePlayerList() []PlayerInfo {
 	return playerList
 }
 
+func (g *Game) CreateScoreMap() map[Team]int {
+	scores := make(map[Team]int, g.settings.TeamCount)
+	for _, info := range GetTeamInfos(g.settings.TeamCount) {
+		scores[info.Id] = g.teamScores[info.Id]
+	}
+	return scores
+}
+
 func (g *Game) GetPlayersCopy() map[string]*Player {
 	g.playerMtx.Lock()
 	defer g.playerMtx.Unlock()
@@ -1033,7 +1699,6 @@ func (g *Game) CreateGameEndedMessage() *GameEndedMessage {
 		TypeProperty: TypeProperty{
 			Type: GameEndedMsg,
 		},
-		RedScore:  g.teamScores[Red],
-		BlueScore: g.teamScores[Blue],
+		Scores: g.CreateScoreMap(),
 	}
 }
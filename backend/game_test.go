@@ -0,0 +1,98 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+// createTestGame returns a game with the given members on the red team, all connected except the listed ones.
+func createTestGame(members []string, disconnected ...string) *Game {
+	g := &Game{
+		players:     make(map[string]*Player),
+		teamPlayers: map[Team][]string{Red: members},
+		hintsGiven:  make(map[string]uint),
+	}
+	for _, id := range members {
+		g.players[id] = &Player{
+			id:        id,
+			team:      Red,
+			connected: !slices.Contains(disconnected, id),
+		}
+	}
+	return g
+}
+
+func TestSelectTeamPlayers(t *testing.T) {
+	tests := []struct {
+		name         string
+		members      []string
+		hintsGiven   map[string]uint
+		disconnected []string
+		wantHint     string
+		wantGuessers []string
+	}{
+		{
+			name:         "first member breaks a tie",
+			members:      []string{"a", "b", "c"},
+			wantHint:     "a",
+			wantGuessers: []string{"b", "c"},
+		},
+		{
+			name:         "fewest hints given",
+			members:      []string{"a", "b", "c"},
+			hintsGiven:   map[string]uint{"a": 1, "b": 1},
+			wantHint:     "c",
+			wantGuessers: []string{"a", "b"},
+		},
+		{
+			name:         "earlier member among fewest hints",
+			members:      []string{"a", "b", "c"},
+			hintsGiven:   map[string]uint{"a": 2, "b": 1, "c": 1},
+			wantHint:     "b",
+			wantGuessers: []string{"a", "c"},
+		},
+		{
+			name:         "disconnected member sits out",
+			members:      []string{"a", "b", "c"},
+			hintsGiven:   map[string]uint{"b": 1, "c": 1},
+			disconnected: []string{"a"},
+			wantHint:     "b",
+			wantGuessers: []string{"c"},
+		},
+		{
+			name:         "too few connected keeps every member",
+			members:      []string{"a", "b"},
+			disconnected: []string{"a"},
+			wantHint:     "b",
+			wantGuessers: []string{"a"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := createTestGame(tt.members, tt.disconnected...)
+			for id, count := range tt.hintsGiven {
+				g.hintsGiven[id] = count
+			}
+			hintGiverId, guesserIds := g.selectTeamPlayers(Red)
+			if hintGiverId != tt.wantHint {
+				t.Errorf("hint giver = %s, want %s", hintGiverId, tt.wantHint)
+			}
+			if !slices.Equal(guesserIds, tt.wantGuessers) {
+				t.Errorf("guessers = %v, want %v", guesserIds, tt.wantGuessers)
+			}
+			if g.hintsGiven[hintGiverId] != tt.hintsGiven[hintGiverId]+1 {
+				t.Errorf("hints given by %s = %d, want %d", hintGiverId, g.hintsGiven[hintGiverId], tt.hintsGiven[hintGiverId]+1)
+			}
+		})
+	}
+}
+
+func TestSelectTeamPlayersRotation(t *testing.T) {
+	g := createTestGame([]string{"a", "b", "c"})
+	want := []string{"a", "b", "c", "a", "b", "c"}
+	for round, wantHint := range want {
+		if hintGiverId, _ := g.selectTeamPlayers(Red); hintGiverId != wantHint {
+			t.Errorf("round %d: hint giver = %s, want %s", round, hintGiverId, wantHint)
+		}
+	}
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Longest pause between two replayed events, longer gaps are shortened
+const MaxReplayGap = 5 * time.Second
+
+type EventDirection string
+
+const (
+	InboundEvent  EventDirection = "in"
+	OutboundEvent EventDirection = "out"
+)
+
+type HistoryEvent struct {
+	// Unix time of the event in milliseconds
+	Time      int64           `json:"time"`
+	Direction EventDirection  `json:"direction"`
+	Message   json.RawMessage `json:"message"`
+}
+
+type HistoryStorage struct {
+	// Directory with one event log file per game
+	dir string
+}
+
+func CreateHistoryStorage(dir string) (*HistoryStorage, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create history dir %s: %w", dir, err)
+	}
+	return &HistoryStorage{
+		dir: dir,
+	}, nil
+}
+
+func (hs *HistoryStorage) path(gameId string) string {
+	return filepath.Join(hs.dir, gameId+".jsonl")
+}
+
+// Load reads all events of the game, the ID must be a UUID.
+func (hs *HistoryStorage) Load(gameId string) ([]HistoryEvent, error) {
+	if _, err := uuid.Parse(gameId); err != nil {
+		return nil, os.ErrNotExist
+	}
+
+	data, err := os.ReadFile(hs.path(gameId))
+	if err != nil {
+		return nil, err
+	}
+
+	events := []HistoryEvent{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var event HistoryEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			// a crash may leave the last line incomplete
+			slog.Warn("Skipping malformed history event.", "gameId", gameId, "err", err)
+			continue
+		}
+		events = append(events, event)
+	}
+	return events, scanner.Err()
+}
+
+// CreateLog starts the event log of a new game.
+func (hs *HistoryStorage) CreateLog() *GameLog {
+	return &GameLog{
+		id:        generateUUID(),
+		storage:   hs,
+		logMtx:    sync.Mutex{},
+		file:      nil,
+		persisted: false,
+		pending:   []HistoryEvent{},
+	}
+}
+
+// ResumeLog continues appending to the event log of a game restored from a snapshot.
+func (hs *HistoryStorage) ResumeLog(gameId string) *GameLog {
+	gl := hs.CreateLog()
+	gl.id = gameId
+	gl.persisted = true
+	return gl
+}
+
+type GameLog struct {
+	// ID of the recorded game
+	id      string
+	storage *HistoryStorage
+	// Log mutex
+	logMtx sync.Mutex
+	// Open log file, nil until the first event is written
+	file *os.File
+	// Are events written to disk, lobby events are kept in memory until the game starts
+	persisted bool
+	// Events recorded before the log was persisted
+	pending []HistoryEvent
+}
+
+func (gl *GameLog) Id() string {
+	if gl == nil {
+		return ""
+	}
+	gl.logMtx.Lock()
+	defer gl.logMtx.Unlock()
+	return gl.id
+}
+
+func (gl *GameLog) Record(direction EventDirection, msg MessageBase) {
+	if gl == nil {
+		return
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		slog.Error("Failed to marshal history event.", "type", msg.GetType(), "err", err)
+		return
+	}
+	event := HistoryEvent{
+		Time:      time.Now().UnixMilli(),
+		Direction: direction,
+		Message:   data,
+	}
+
+	gl.logMtx.Lock()
+	defer gl.logMtx.Unlock()
+
+	if !gl.persisted {
+		gl.pending = append(gl.pending, event)
+		return
+	}
+	if err := gl.writeUnlocked(event); err != nil {
+		slog.Error("Failed to write history event.", "gameId", gl.id, "err", err)
+	}
+}
+
+// Persist writes events recorded so far to disk and appends every following event directly.
+func (gl *GameLog) Persist() {
+	if gl == nil {
+		return
+	}
+
+	gl.logMtx.Lock()
+	defer gl.logMtx.Unlock()
+
+	if gl.persisted {
+		return
+	}
+	gl.persisted = true
+	for _, event := range gl.pending {
+		if err := gl.writeUnlocked(event); err != nil {
+			slog.Error("Failed to write history event.", "gameId", gl.id, "err", err)
+			break
+		}
+	}
+	gl.pending = nil
+}
+
+// Restart closes the log of the finished game and starts recording a new game.
+func (gl *GameLog) Restart() {
+	if gl == nil {
+		return
+	}
+
+	gl.logMtx.Lock()
+	defer gl.logMtx.Unlock()
+
+	gl.closeUnlocked()
+	gl.id = generateUUID()
+	gl.persisted = false
+	gl.pending = []HistoryEvent{}
+}
+
+func (gl *GameLog) Close() {
+	if gl == nil {
+		return
+	}
+
+	gl.logMtx.Lock()
+	defer gl.logMtx.Unlock()
+	gl.closeUnlocked()
+}
+
+func (gl *GameLog) closeUnlocked() {
+	if gl.file == nil {
+		return
+	}
+	if err := gl.file.Close(); err != nil {
+		slog.Error("Failed to close history file.", "gameId", gl.id, "err", err)
+	}
+	gl.file = nil
+}
+
+func (gl *GameLog) writeUnlocked(event HistoryEvent) error {
+	if gl.file == nil {
+		file, err := os.OpenFile(gl.storage.path(gl.id), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			return fmt.Errorf("failed to open history file: %w", err)
+		}
+		gl.file = file
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal history event: %w", err)
+	}
+	if _, err := gl.file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to append history event: %w", err)
+	}
+	return nil
+}
+
+// ReplayGame re-sends outbound messages of a recorded game to the client, keeping their original
+// spacing divided by speed.
+func ReplayGame(ctx context.Context, conn *Connection, gameId string, speed int, events []HistoryEvent) {
+	startedMsg := &ReplayStartedMessage{
+		TypeProperty: TypeProperty{
+			Type: ReplayStartedMsg,
+		},
+		GameId: gameId,
+		Speed:  speed,
+	}
+	if err := SendDirectMessage(conn, startedMsg); err != nil {
+		slog.Warn("Failed to send replay started message.", "err", err)
+		return
+	}
+
+	var last int64
+	for _, event := range events {
+		if event.Direction != OutboundEvent {
+			continue
+		}
+		if last != 0 {
+			gap := min(time.Duration(event.Time-last)*time.Millisecond, MaxReplayGap)
+			select {
+			case <-time.After(gap / time.Duration(speed)):
+			case <-ctx.Done():
+				return
+			}
+		}
+		last = event.Time
+		if err := conn.SendContext(ctx, event.Message); err != nil {
+			slog.Warn("Failed to send replayed message.", "gameId", gameId, "err", err)
+			return
+		}
+	}
+
+	endedMsg := &ReplayEndedMessage{
+		TypeProperty: TypeProperty{
+			Type: ReplayEndedMsg,
+		},
+		GameId: gameId,
+	}
+	if err := SendDirectMessage(conn, endedMsg); err != nil {
+		slog.Warn("Failed to send replay ended message.", "err", err)
+	}
+}
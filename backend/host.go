@@ -0,0 +1,212 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// checkHostUnlocked reports an error to the player if they are not the room host.
+func (g *Game) checkHostUnlocked(player *Player, msgType MessageType) error {
+	if g.hostId == player.id {
+		return nil
+	}
+	SendErrorMessage(
+		player,
+		*CreateErrorMessage(
+			msgType,
+			ErrNotHost,
+		),
+	)
+	return fmt.Errorf("player %s is not the host, cannot perform %s", player.id, msgType)
+}
+
+func (g *Game) kickPlayer(playerId string, targetId string) error {
+	g.playerMtx.Lock()
+	defer g.playerMtx.Unlock()
+
+	player, exists := g.players[playerId]
+	if !exists {
+		return fmt.Errorf("player with ID %s not found", playerId)
+	}
+
+	if err := g.checkHostUnlocked(player, KickPlayerMsg); err != nil {
+		return err
+	}
+
+	if g.gameState != InLobby {
+		SendErrorMessage(
+			player,
+			*CreateErrorMessage(
+				KickPlayerMsg,
+				ErrGameNotInLobby,
+			),
+		)
+		return fmt.Errorf("game not in lobby state, cannot kick player")
+	}
+
+	if targetId == playerId {
+		SendErrorMessage(
+			player,
+			*CreateErrorMessage(
+				KickPlayerMsg,
+				ErrCannotKickSelf,
+			),
+		)
+		return fmt.Errorf("host cannot kick themselves")
+	}
+
+	target, exists := g.players[targetId]
+	if !exists {
+		SendErrorMessage(
+			player,
+			*CreateErrorMessage(
+				KickPlayerMsg,
+				ErrTargetNotFound,
+			),
+		)
+		return fmt.Errorf("player with ID %s not found, cannot kick", targetId)
+	}
+
+	for i, v := range g.teamPlayers[target.team] {
+		if v == targetId {
+			g.teamPlayers[target.team] = append(g.teamPlayers[target.team][:i], g.teamPlayers[target.team][i+1:]...)
+			break
+		}
+	}
+	delete(g.players, targetId)
+	g.lastActivity = time.Now()
+	slog.Info("Player kicked from the room.", "roomCode", g.code, "playerId", targetId, "hostId", playerId)
+
+	players := g.GetPlayersCopyUnlocked()
+	kickedMsg := &PlayerKickedMessage{
+		TypeProperty: TypeProperty{
+			Type: PlayerKickedMsg,
+		},
+		PlayerIdProperty: PlayerIdProperty{
+			PlayerId: targetId,
+		},
+	}
+	g.BroadcastMessage(players, kickedMsg, nil)
+
+	if target.conn != nil {
+		// let the kicked player know before the connection is closed
+		if err := SendUnicastMessage(target, kickedMsg); err != nil {
+			slog.Warn("Failed to send player kicked message.", "playerId", targetId, "err", err)
+		}
+		target.conn.CloseWithFrame(websocket.ClosePolicyViolation, "kicked from the room")
+	}
+	return nil
+}
+
+func (g *Game) movePlayer(playerId string, targetId string, team Team) error {
+	g.playerMtx.Lock()
+	defer g.playerMtx.Unlock()
+
+	player, exists := g.players[playerId]
+	if !exists {
+		return fmt.Errorf("player with ID %s not found", playerId)
+	}
+
+	if err := g.checkHostUnlocked(player, MovePlayerMsg); err != nil {
+		return err
+	}
+
+	target, exists := g.players[targetId]
+	if !exists {
+		SendErrorMessage(
+			player,
+			*CreateErrorMessage(
+				MovePlayerMsg,
+				ErrTargetNotFound,
+			),
+		)
+		return fmt.Errorf("player with ID %s not found, cannot move", targetId)
+	}
+
+	// the host may move players even when teams are locked
+	return g.assignTeamUnlocked(player, target, team, MovePlayerMsg)
+}
+
+func (g *Game) transferHost(playerId string, targetId string) error {
+	g.playerMtx.Lock()
+	defer g.playerMtx.Unlock()
+
+	player, exists := g.players[playerId]
+	if !exists {
+		return fmt.Errorf("player with ID %s not found", playerId)
+	}
+
+	if err := g.checkHostUnlocked(player, TransferHostMsg); err != nil {
+		return err
+	}
+
+	target, exists := g.players[targetId]
+	if !exists || !target.connected {
+		SendErrorMessage(
+			player,
+			*CreateErrorMessage(
+				TransferHostMsg,
+				ErrTargetNotFound,
+			),
+		)
+		return fmt.Errorf("player with ID %s not connected, cannot transfer host", targetId)
+	}
+
+	if targetId == playerId {
+		return nil
+	}
+	g.hostId = targetId
+
+	players := g.GetPlayersCopyUnlocked()
+	hostChangedMsg := &HostChangedMessage{
+		TypeProperty: TypeProperty{
+			Type: HostChangedMsg,
+		},
+		PlayerIdProperty: PlayerIdProperty{
+			PlayerId: targetId,
+		},
+	}
+	g.BroadcastMessage(players, hostChangedMsg, nil)
+	return nil
+}
+
+// startGame lets the host start the game once all teams are filled, without waiting for everyone to be ready.
+func (g *Game) startGame(playerId string) (bool, error) {
+	g.playerMtx.RLock()
+	defer g.playerMtx.RUnlock()
+
+	player, exists := g.players[playerId]
+	if !exists {
+		return false, fmt.Errorf("player with ID %s not found", playerId)
+	}
+
+	if err := g.checkHostUnlocked(player, StartGameMsg); err != nil {
+		return false, err
+	}
+
+	if g.gameState != InLobby {
+		SendErrorMessage(
+			player,
+			*CreateErrorMessage(
+				StartGameMsg,
+				ErrGameNotInLobby,
+			),
+		)
+		return false, fmt.Errorf("game not in lobby state, cannot start game")
+	}
+
+	if !g.AllTeamsFilled() {
+		SendErrorMessage(
+			player,
+			*CreateErrorMessage(
+				StartGameMsg,
+				ErrTeamsNotFilled,
+			),
+		)
+		return false, fmt.Errorf("not every team has enough players, cannot start game")
+	}
+	return true, nil
+}
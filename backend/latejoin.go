@@ -0,0 +1,202 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// QueuedPlayer is a player who joined a running game and waits for a team until the next round.
+type QueuedPlayer struct {
+	PlayerId string `json:"playerId"`
+	// Preferred team, unassigned to join the smallest team with a free spot
+	Team Team `json:"team"`
+}
+
+// AcceptsLateJoiners reports whether new players have to wait for the next round to join a team.
+func (g *Game) AcceptsLateJoiners() bool {
+	return g.gameState != InLobby && g.gameState != Ended
+}
+
+func (g *Game) queueIndexUnlocked(playerId string) int {
+	for i, queued := range g.joinQueue {
+		if queued.PlayerId == playerId {
+			return i
+		}
+	}
+	return -1
+}
+
+// dequeuePlayerUnlocked removes the player from the join queue, returns false if they were not queued.
+func (g *Game) dequeuePlayerUnlocked(playerId string) bool {
+	idx := g.queueIndexUnlocked(playerId)
+	if idx < 0 {
+		return false
+	}
+	g.joinQueue = append(g.joinQueue[:idx], g.joinQueue[idx+1:]...)
+	return true
+}
+
+func (g *Game) createPlayerQueuedMessage(queued QueuedPlayer) *PlayerQueuedMessage {
+	return &PlayerQueuedMessage{
+		TypeProperty: TypeProperty{
+			Type: PlayerQueuedMsg,
+		},
+		PlayerIdProperty: PlayerIdProperty{
+			PlayerId: queued.PlayerId,
+		},
+		Team:     queued.Team,
+		Position: g.queueIndexUnlocked(queued.PlayerId),
+	}
+}
+
+// queueTeamUnlocked stores the team a queued player wants to join at the next round boundary,
+// errors are reported to the requesting player.
+func (g *Game) queueTeamUnlocked(requester *Player, target *Player, team Team, msgType MessageType) error {
+	idx := g.queueIndexUnlocked(target.id)
+
+	if team != Unassigned && !team.IsPlaying(g.settings.TeamCount) {
+		SendErrorMessage(
+			requester,
+			*CreateErrorMessage(
+				msgType,
+				ErrInvalidTeam,
+			),
+		)
+		return nil
+	}
+
+	if team != Unassigned && len(g.teamPlayers[team]) >= g.settings.MaxTeamMembers {
+		SendErrorMessage(
+			requester,
+			*CreateErrorMessage(
+				msgType,
+				ErrTeamFull,
+			),
+		)
+		return nil
+	}
+
+	if g.joinQueue[idx].Team == team {
+		return nil
+	}
+	g.joinQueue[idx].Team = team
+	slog.Debug("Queued player chose team", "player_id", target.id, "team", team)
+
+	players := g.GetPlayersCopyUnlocked()
+	return g.BroadcastMessage(players, g.createPlayerQueuedMessage(g.joinQueue[idx]), nil)
+}
+
+// slotQueuedPlayersUnlocked assigns queued players to their preferred team, or the smallest team
+// with a free spot, at a round boundary. Players stay queued while every team is full.
+func (g *Game) slotQueuedPlayersUnlocked() {
+	if len(g.joinQueue) == 0 {
+		return
+	}
+
+	players := g.GetPlayersCopyUnlocked()
+	waiting := make([]QueuedPlayer, 0, len(g.joinQueue))
+	for _, queued := range g.joinQueue {
+		player, exists := g.players[queued.PlayerId]
+		if !exists {
+			continue
+		}
+		team := g.selectLateJoinerTeamUnlocked(queued.Team)
+		if team == Unassigned {
+			waiting = append(waiting, queued)
+			continue
+		}
+
+		// join the hint giver rotation where the team currently is instead of giving the next hints in a row
+		g.hintsGiven[player.id] = g.fewestHintsGivenUnlocked(team)
+		player.SetTeam(team)
+		g.teamPlayers[team] = append(g.teamPlayers[team], player.id)
+		slog.Info("Queued player joined team.", "roomCode", g.code, "playerId", player.id, "team", team)
+
+		teamChangedMsg := &TeamChangedMessage{
+			TypeProperty: TypeProperty{
+				Type: TeamChangedMsg,
+			},
+			PlayerIdProperty: PlayerIdProperty{
+				PlayerId: player.id,
+			},
+			Team: team,
+		}
+		g.BroadcastMessage(players, teamChangedMsg, nil)
+	}
+	g.joinQueue = waiting
+}
+
+func (g *Game) selectLateJoinerTeamUnlocked(preferred Team) Team {
+	if preferred.IsPlaying(g.settings.TeamCount) && len(g.teamPlayers[preferred]) < g.settings.MaxTeamMembers {
+		return preferred
+	}
+	team := Unassigned
+	for _, info := range GetTeamInfos(g.settings.TeamCount) {
+		size := len(g.teamPlayers[info.Id])
+		if size >= g.settings.MaxTeamMembers {
+			continue
+		}
+		if team == Unassigned || size < len(g.teamPlayers[team]) {
+			team = info.Id
+		}
+	}
+	return team
+}
+
+func (g *Game) fewestHintsGivenUnlocked(team Team) uint {
+	members := g.teamPlayers[team]
+	if len(members) == 0 {
+		return 0
+	}
+	fewest := g.hintsGiven[members[0]]
+	for _, id := range members[1:] {
+		fewest = min(fewest, g.hintsGiven[id])
+	}
+	return fewest
+}
+
+// sendLateJoinStateUnlocked tells a player who joined a running game about the game state, the current
+// round with its timing and their place in the queue.
+func (g *Game) sendLateJoinStateUnlocked(player *Player) error {
+	stateMsg := &GameStateChangedMessage{
+		TypeProperty: TypeProperty{
+			Type: GameStateChangedMsg,
+		},
+		State: g.gameState,
+	}
+	if err := SendUnicastMessage(player, stateMsg); err != nil {
+		return fmt.Errorf("failed to send game state to late joiner %s: %w", player.id, err)
+	}
+	if err := g.sendRoundStateUnlocked(player); err != nil {
+		return fmt.Errorf("failed to send round state to late joiner %s: %w", player.id, err)
+	}
+	idx := g.queueIndexUnlocked(player.id)
+	if idx < 0 {
+		return nil
+	}
+	return g.BroadcastMessage(g.GetPlayersCopyUnlocked(), g.createPlayerQueuedMessage(g.joinQueue[idx]), nil)
+}
+
+// sendRoundStateUnlocked sends the current round setup, with the words redacted for players who may not
+// see them, followed by the round timing if the round has started.
+func (g *Game) sendRoundStateUnlocked(player *Player) error {
+	if g.currentRound == nil {
+		return nil
+	}
+	setupMsg := g.currentRound.CreateRoundSetupMessage()
+	var msg MessageBase = setupMsg
+	if !g.CanSeeWordsUnlocked(player) {
+		msg = setupMsg.Redact()
+	}
+	if err := SendUnicastMessage(player, msg); err != nil {
+		return err
+	}
+
+	switch g.gameState {
+	case InRound:
+		return SendUnicastMessage(player, g.currentRound.CreateRoundStartedMessage())
+	case Paused:
+		return SendUnicastMessage(player, g.currentRound.CreateRoundPausedMessage())
+	}
+	return nil
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+type LintIssue struct {
+	// Line of the word the issue was found in, 0 if not tied to a word
+	Line    int
+	Message string
+}
+
+type lintEntry struct {
+	line int
+	raw  []byte
+	word *TabooWord
+}
+
+// runCommand executes a command line subcommand and returns the process exit code.
+func runCommand(args []string) int {
+	if len(args) == 3 && args[0] == "words" && args[1] == "lint" {
+		return lintWordsCommand(args[2])
+	}
+	fmt.Fprintln(os.Stderr, "usage: taboo-server words lint <file>")
+	return 2
+}
+
+func lintWordsCommand(file string) int {
+	ss, err := GetSchemaStorage()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load schemas: %s\n", err)
+		return 2
+	}
+
+	issues, err := LintWordFile(ss, file)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", file, err)
+		return 2
+	}
+
+	for _, issue := range issues {
+		fmt.Printf("%s:%d: %s\n", file, issue.Line, issue.Message)
+	}
+	if len(issues) > 0 {
+		fmt.Printf("%d issue(s) found\n", len(issues))
+		return 1
+	}
+	return 0
+}
+
+// LintWordFile checks a deck file, a plain JSON array of words or a CSV word list for problems
+// that would otherwise only surface mid-game.
+func LintWordFile(ss *SchemaStorage, file string) ([]LintIssue, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	var entries []lintEntry
+	if strings.EqualFold(filepath.Ext(file), ".csv") {
+		entries, err = readCSVEntries(data)
+	} else {
+		entries, err = readJSONEntries(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	issues := []LintIssue{}
+	idLines := make(map[uint]int)
+	wordLines := make(map[string]int)
+	foldedWords := make(map[string]*lintEntry)
+	for i := range entries {
+		entry := &entries[i]
+		if err := ss.validate(TabooWordSchema, entry.raw); err != nil {
+			issues = append(issues, LintIssue{entry.line, err.Error()})
+		}
+		word := entry.word
+		if word == nil {
+			continue
+		}
+
+		if word.ID != 0 {
+			if line, exists := idLines[word.ID]; exists {
+				issues = append(issues, LintIssue{entry.line, fmt.Sprintf("duplicate ID %d, first used on line %d", word.ID, line)})
+			} else {
+				idLines[word.ID] = entry.line
+			}
+		}
+
+		folded := strings.ToLower(strings.TrimSpace(word.Word))
+		if line, exists := wordLines[word.Word]; exists {
+			issues = append(issues, LintIssue{entry.line, fmt.Sprintf("duplicate word %q, first used on line %d", word.Word, line)})
+		} else if other, exists := foldedWords[folded]; exists && folded != "" {
+			issues = append(issues, LintIssue{entry.line, fmt.Sprintf("word %q differs only in case from %q on line %d", word.Word, other.word.Word, other.line)})
+		}
+		if _, exists := wordLines[word.Word]; !exists {
+			wordLines[word.Word] = entry.line
+		}
+		if _, exists := foldedWords[folded]; !exists {
+			foldedWords[folded] = entry
+		}
+
+		seenTaboos := make(map[string]string)
+		for _, taboo := range word.Taboos {
+			foldedTaboo := strings.ToLower(strings.TrimSpace(taboo))
+			if containsWords(foldedTaboo, folded) {
+				issues = append(issues, LintIssue{entry.line, fmt.Sprintf("taboo %q contains the word %q itself", taboo, word.Word)})
+			}
+			if other, exists := seenTaboos[foldedTaboo]; exists && other != taboo {
+				issues = append(issues, LintIssue{entry.line, fmt.Sprintf("taboo %q differs only in case from %q", taboo, other)})
+			} else if !exists {
+				seenTaboos[foldedTaboo] = taboo
+			}
+		}
+	}
+
+	slices.SortStableFunc(issues, func(a, b LintIssue) int {
+		return a.Line - b.Line
+	})
+	return issues, nil
+}
+
+// containsWords reports whether the words of word appear in a row in text, both split on spaces and hyphens.
+func containsWords(text, word string) bool {
+	splitWords := func(s string) []string {
+		return strings.FieldsFunc(s, func(r rune) bool {
+			return unicode.IsSpace(r) || r == '-'
+		})
+	}
+	wordParts := splitWords(word)
+	textParts := splitWords(text)
+	if len(wordParts) == 0 {
+		return false
+	}
+	for i := 0; i+len(wordParts) <= len(textParts); i++ {
+		if slices.Equal(textParts[i:i+len(wordParts)], wordParts) {
+			return true
+		}
+	}
+	return false
+}
+
+// readJSONEntries reads words from a deck object or a bare array, keeping the line each word starts on.
+func readJSONEntries(data []byte) ([]lintEntry, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+
+	switch tok {
+	case json.Delim('['):
+		return readJSONWordArray(dec, data)
+	case json.Delim('{'):
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse JSON: %w", err)
+			}
+			if key != "words" {
+				var skipped json.RawMessage
+				if err := dec.Decode(&skipped); err != nil {
+					return nil, fmt.Errorf("failed to parse JSON: %w", err)
+				}
+				continue
+			}
+			if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+				return nil, fmt.Errorf("line %d: words must be an array", lineAt(data, dec.InputOffset()))
+			}
+			return readJSONWordArray(dec, data)
+		}
+		return nil, fmt.Errorf("deck has no words array")
+	default:
+		return nil, fmt.Errorf("expected a deck object or an array of words")
+	}
+}
+
+func readJSONWordArray(dec *json.Decoder, data []byte) ([]lintEntry, error) {
+	entries := []lintEntry{}
+	for dec.More() {
+		line := lineAt(data, dec.InputOffset())
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, fmt.Errorf("line %d: failed to parse JSON: %w", line, err)
+		}
+		entry := lintEntry{line: line, raw: raw}
+		var word TabooWord
+		if err := json.Unmarshal(raw, &word); err == nil {
+			entry.word = &word
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// lineAt returns the line of the first value following the given offset.
+func lineAt(data []byte, offset int64) int {
+	i := int(offset)
+	for i < len(data) && strings.ContainsRune(" \t\r\n,:", rune(data[i])) {
+		i++
+	}
+	return bytes.Count(data[:i], []byte("\n")) + 1
+}
+
+func readCSVEntries(data []byte) ([]lintEntry, error) {
+	words, lines, err := parseCSVWords(string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]lintEntry, 0, len(words))
+	for i, word := range words {
+		// CSV lists carry no IDs, give each row a unique one so only the row contents are checked
+		word.ID = uint(i + 1)
+		raw, err := json.Marshal(word)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: failed to marshal word: %w", lines[i], err)
+		}
+		entries = append(entries, lintEntry{line: lines[i], raw: raw, word: word})
+	}
+	return entries, nil
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLintWordFile(t *testing.T) {
+	ss, err := GetSchemaStorage()
+	if err != nil {
+		t.Fatalf("failed to load schemas: %s", err)
+	}
+
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		// issues expected in order, messages only need to contain the given text
+		want []LintIssue
+	}{
+		{
+			name: "clean deck",
+			file: "deck.json",
+			content: `{"id": "test", "words": [
+  {"id": 1, "word": "Beach", "taboo": ["Sand", "Sea", "Sun", "Swim", "Waves"]}
+]}`,
+			want: []LintIssue{},
+		},
+		{
+			name: "lines of a multi-line array",
+			file: "words.json",
+			content: `[
+  {"id": 1, "word": "Beach", "taboo": ["Sand", "Sea", "Sun", "Swim", "Waves"]},
+  {
+    "id": 1,
+    "word": "Winter",
+    "taboo": ["Cold", "Snow", "Season", "December", "Ice"]
+  }
+]`,
+			want: []LintIssue{{3, "duplicate ID 1, first used on line 2"}},
+		},
+		{
+			name: "schema violation",
+			file: "words.json",
+			content: `[
+  {"id": 1, "word": "Beach", "taboo": ["Sand", "Sea", "Sun", "Swim", "Waves"]},
+  {"id": 2, "word": "Winter", "taboo": ["Cold"]}
+]`,
+			want: []LintIssue{{3, "not valid against schema"}},
+		},
+		{
+			name: "exact duplicate word",
+			file: "words.json",
+			content: `[
+  {"id": 1, "word": "Beach", "taboo": ["Sand", "Sea", "Sun", "Swim", "Waves"]},
+  {"id": 2, "word": "Beach", "taboo": ["Towel", "Shore", "Shell", "Tide", "Coast"]}
+]`,
+			want: []LintIssue{{3, `duplicate word "Beach", first used on line 2`}},
+		},
+		{
+			name: "case-insensitive duplicate word",
+			file: "words.json",
+			content: `[
+  {"id": 1, "word": "Beach", "taboo": ["Sand", "Sea", "Sun", "Swim", "Waves"]},
+  {"id": 2, "word": "beach ", "taboo": ["Towel", "Shore", "Shell", "Tide", "Coast"]}
+]`,
+			want: []LintIssue{{3, `word "beach " differs only in case from "Beach" on line 2`}},
+		},
+		{
+			name: "case-insensitive duplicate taboo",
+			file: "words.json",
+			content: `[
+  {"id": 1, "word": "Beach", "taboo": ["Sand", "Sea", "Sun", "Swim", "sand"]}
+]`,
+			want: []LintIssue{{2, `taboo "sand" differs only in case from "Sand"`}},
+		},
+		{
+			name: "self-referential taboo",
+			file: "words.json",
+			content: `[
+  {"id": 1, "word": "Light", "taboo": ["Lamp", "Bright", "Light-year", "Daylight", "Dark"]},
+  {"id": 2, "word": "Traffic Light", "taboo": ["Red", "Green", "Stop", "Road", "traffic light pole"]}
+]`,
+			want: []LintIssue{
+				{2, `taboo "Light-year" contains the word "Light" itself`},
+				{3, `taboo "traffic light pole" contains the word "Traffic Light" itself`},
+			},
+		},
+		{
+			name: "taboo containing the word as part of another word",
+			file: "words.json",
+			content: `[
+  {"id": 1, "word": "Sea", "taboo": ["Season", "Water", "Salt", "Fish", "Wave"]},
+  {"id": 2, "word": "Art", "taboo": ["Party", "Paint", "Museum", "Gallery", "Artist"]}
+]`,
+			want: []LintIssue{},
+		},
+		{
+			name: "CSV lines after a header",
+			file: "words.csv",
+			content: `word,taboo1,taboo2,taboo3,taboo4,taboo5
+Beach,Sand,Sea,Sun,Swim,Waves
+Winter,Cold,Snow,Season,December,Winter sport
+BEACH,Towel,Shore,Shell,Tide,Coast
+`,
+			want: []LintIssue{
+				{3, `taboo "Winter sport" contains the word "Winter" itself`},
+				{4, `word "BEACH" differs only in case from "Beach" on line 2`},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(file, []byte(tt.content), 0o644); err != nil {
+				t.Fatalf("failed to write word file: %s", err)
+			}
+
+			issues, err := LintWordFile(ss, file)
+			if err != nil {
+				t.Fatalf("LintWordFile() error = %s", err)
+			}
+			if len(issues) != len(tt.want) {
+				t.Fatalf("LintWordFile() = %v, want %v", issues, tt.want)
+			}
+			for i, want := range tt.want {
+				if issues[i].Line != want.Line || !strings.Contains(issues[i].Message, want.Message) {
+					t.Errorf("issue %d = %v, want %v", i, issues[i], want)
+				}
+			}
+		})
+	}
+}
@@ -1,30 +1,49 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/gorilla/websocket"
 )
 
-func playerConnHandler(game *Game, w http.ResponseWriter, r *http.Request) {
-	conn, err := upgrader.Upgrade(w, r, nil)
+func playerConnHandler(registry *RoomRegistry, w http.ResponseWriter, r *http.Request) {
+	ws, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		slog.Error("Error accepting client connection.", "err", err)
 		return
 	}
+	conn := CreateConnection(ws)
 
 	ss, _ := GetSchemaStorage()
 
 	go func() {
 
 		var playerId string
+		var game *Game
+		// is the connection watching the game instead of playing
+		var spectating bool
+		// cancels a running replay, the replay must stop before anything else is queued on the connection
+		stopReplay := func() {}
 
 		defer func() {
-			if playerId != "" {
-				game.RemovePlayer(playerId)
+			stopReplay()
+			if game != nil && playerId != "" {
+				if spectating {
+					game.RemoveSpectator(playerId)
+				} else {
+					game.RemovePlayer(playerId)
+				}
 			}
 			slog.Info("Client disconnected.", "playerId", playerId)
 			conn.Close()
@@ -36,6 +55,7 @@ func playerConnHandler(game *Game, w http.ResponseWriter, r *http.Request) {
 				slog.Error("Failed to read message.", "err", err)
 				break
 			}
+			receivedAt := time.Now().UnixMilli()
 			slog.Debug("Incoming message.", "type", mtype, "content", data)
 
 			msg, err := decodeIncomingMessage(ss, data)
@@ -44,25 +64,231 @@ func playerConnHandler(game *Game, w http.ResponseWriter, r *http.Request) {
 				continue
 			}
 
+			if msg.GetType() == TimeSyncMsg {
+				// answered right away without stopping a running replay
+				syncMsg, ok := msg.(*TimeSyncMessage)
+				if !ok {
+					slog.Error("Failed to cast message to TimeSyncMessage")
+					continue
+				}
+				ackMsg := &TimeSyncAckMessage{
+					TypeProperty: TypeProperty{
+						Type: TimeSyncAckMsg,
+					},
+					ClientTime:        syncMsg.ClientTime,
+					ServerReceiveTime: receivedAt,
+					ServerSendTime:    time.Now().UnixMilli(),
+				}
+				if err := SendDirectMessage(conn, ackMsg); err != nil {
+					slog.Warn("Failed to send time sync ack message.", "err", err)
+				}
+				continue
+			}
+			stopReplay()
+
 			if msg.GetType() == ConnectMsg {
 				conMsg, ok := msg.(*ConnectMessage)
 				if !ok {
 					slog.Error("Failed to cast message to ConnectMessage")
 					continue
 				}
-				playerId, err = game.AddPlayer(conn, conMsg.Name)
+				if game != nil {
+					slog.Error("Player already connected to a room", "playerId", playerId, "roomCode", game.code)
+					continue
+				}
+				profileId, ok := authenticateProfile(conn, registry, ConnectMsg, conMsg.Name, conMsg.Secret)
+				if !ok {
+					continue
+				}
+				if conMsg.RoomCode == "" {
+					game, ok = createRoom(conn, registry, ConnectMsg)
+					if !ok {
+						continue
+					}
+				} else {
+					var exists bool
+					game, exists = registry.GetRoom(conMsg.RoomCode)
+					if !exists {
+						SendDirectErrorMessage(
+							conn,
+							*CreateErrorMessage(
+								ConnectMsg,
+								ErrRoomNotFound,
+							),
+						)
+						slog.Error("Room not found", "roomCode", conMsg.RoomCode)
+						break
+					}
+				}
+				playerId, err = game.AddPlayer(conn, conMsg.Name, profileId)
 				if err != nil {
 					slog.Error("Failed to add player")
 					break
 				}
 				slog.Debug("Player ID stored", "playerId", playerId)
 				continue
+			} else if msg.GetType() == JoinRoomMsg {
+				joinMsg, ok := msg.(*JoinRoomMessage)
+				if !ok {
+					slog.Error("Failed to cast message to JoinRoomMessage")
+					continue
+				}
+				if game != nil {
+					slog.Error("Player already connected to a room", "playerId", playerId, "roomCode", game.code)
+					continue
+				}
+				profileId, ok := authenticateProfile(conn, registry, JoinRoomMsg, joinMsg.Name, joinMsg.Secret)
+				if !ok {
+					continue
+				}
+				var exists bool
+				game, exists = registry.GetRoom(joinMsg.RoomCode)
+				if !exists {
+					SendDirectErrorMessage(
+						conn,
+						*CreateErrorMessage(
+							JoinRoomMsg,
+							ErrRoomNotFound,
+						),
+					)
+					slog.Error("Room not found", "roomCode", joinMsg.RoomCode)
+					continue
+				}
+				playerId, err = game.AddPlayer(conn, joinMsg.Name, profileId)
+				if err != nil {
+					slog.Error("Failed to add player", "roomCode", joinMsg.RoomCode, "err", err)
+					break
+				}
+				slog.Debug("Player ID stored", "playerId", playerId)
+				continue
+			} else if msg.GetType() == SpectateMsg {
+				spectateMsg, ok := msg.(*SpectateMessage)
+				if !ok {
+					slog.Error("Failed to cast message to SpectateMessage")
+					continue
+				}
+				if game != nil {
+					slog.Error("Player already connected to a room", "playerId", playerId, "roomCode", game.code)
+					continue
+				}
+				room, exists := registry.GetRoom(spectateMsg.RoomCode)
+				if !exists {
+					SendDirectErrorMessage(
+						conn,
+						*CreateErrorMessage(
+							SpectateMsg,
+							ErrRoomNotFound,
+						),
+					)
+					slog.Error("Room not found", "roomCode", spectateMsg.RoomCode)
+					continue
+				}
+				playerId, err = room.AddSpectator(conn, spectateMsg.Name)
+				if err != nil {
+					slog.Error("Failed to add spectator", "roomCode", spectateMsg.RoomCode, "err", err)
+					continue
+				}
+				game = room
+				spectating = true
+				slog.Debug("Spectator ID stored", "playerId", playerId)
+				continue
+			} else if msg.GetType() == CreateRoomMsg {
+				newGame, ok := createRoom(conn, registry, CreateRoomMsg)
+				if !ok {
+					continue
+				}
+				createdMsg := &RoomCreatedMessage{
+					TypeProperty: TypeProperty{
+						Type: RoomCreatedMsg,
+					},
+					RoomCode: newGame.code,
+				}
+				if err := SendDirectMessage(conn, createdMsg); err != nil {
+					slog.Warn("Failed to send room created message.", "err", err)
+				}
+				continue
+			} else if msg.GetType() == RoomListMsg {
+				listMsg := &RoomListMessage{
+					TypeProperty: TypeProperty{
+						Type: RoomListMsg,
+					},
+					Rooms: registry.ListRooms(),
+				}
+				if err := SendDirectMessage(conn, listMsg); err != nil {
+					slog.Warn("Failed to send room list message.", "err", err)
+				}
+				continue
+			} else if msg.GetType() == GetLeaderboardMsg {
+				leaderboardMsg := &LeaderboardMessage{
+					TypeProperty: TypeProperty{
+						Type: LeaderboardMsg,
+					},
+					Players: registry.profiles.GetLeaderboard(LeaderboardSize),
+				}
+				if err := SendDirectMessage(conn, leaderboardMsg); err != nil {
+					slog.Warn("Failed to send leaderboard message.", "err", err)
+				}
+				continue
+			} else if msg.GetType() == ReplayGameMsg {
+				replayMsg, ok := msg.(*ReplayGameMessage)
+				if !ok {
+					slog.Error("Failed to cast message to ReplayGameMessage")
+					continue
+				}
+				if game != nil {
+					slog.Error("Player already connected to a room", "playerId", playerId, "roomCode", game.code)
+					continue
+				}
+				events, err := registry.history.Load(replayMsg.GameId)
+				if err != nil {
+					SendDirectErrorMessage(
+						conn,
+						*CreateErrorMessage(
+							ReplayGameMsg,
+							ErrGameHistoryNotFound,
+						),
+					)
+					slog.Error("Failed to load game history", "gameId", replayMsg.GameId, "err", err)
+					continue
+				}
+				ctx, cancel := context.WithCancel(context.Background())
+				done := make(chan struct{})
+				go func() {
+					defer close(done)
+					ReplayGame(ctx, conn, replayMsg.GameId, replayMsg.Speed, events)
+				}()
+				stopReplay = func() {
+					cancel()
+					<-done
+				}
+				continue
 			} else if msg.GetType() == ReconnectMsg {
 				reconMsg, ok := msg.(*ReconnectMessage)
 				if !ok {
 					slog.Error("Failed to cast message to ReconnectMessage")
 					continue
 				}
+				if game != nil {
+					slog.Error("Player already connected to a room", "playerId", playerId, "roomCode", game.code)
+					continue
+				}
+				var exists bool
+				if reconMsg.RoomCode == "" {
+					game, exists = registry.FindRoomByPlayer(reconMsg.PlayerId)
+				} else {
+					game, exists = registry.GetRoom(reconMsg.RoomCode)
+				}
+				if !exists {
+					SendDirectErrorMessage(
+						conn,
+						*CreateErrorMessage(
+							ReconnectMsg,
+							ErrRoomNotFound,
+						),
+					)
+					slog.Error("Room not found", "roomCode", reconMsg.RoomCode, "playerId", reconMsg.PlayerId)
+					break
+				}
 				playerId = reconMsg.PlayerId
 				err = game.ReconnectPlayer(conn, playerId, reconMsg.SessionToken)
 				if err != nil {
@@ -72,6 +298,21 @@ func playerConnHandler(game *Game, w http.ResponseWriter, r *http.Request) {
 				slog.Debug("Player ID stored after reconnection", "playerId", playerId)
 				continue
 			} else {
+				if game == nil {
+					slog.Error("Player not connected to a room", "type", msg.GetType())
+					continue
+				}
+				if spectating {
+					SendDirectErrorMessage(
+						conn,
+						*CreateErrorMessage(
+							msg.GetType(),
+							ErrSpectatorAction,
+						),
+					)
+					slog.Error("Spectators cannot send game actions", "playerId", playerId, "type", msg.GetType())
+					continue
+				}
 				playerMsg, ok := msg.(PlayerMessage)
 				if !ok {
 					slog.Error("Failed to cast message to PlayerMessage")
@@ -88,6 +329,77 @@ func playerConnHandler(game *Game, w http.ResponseWriter, r *http.Request) {
 	}()
 }
 
+// authenticateProfile resolves the profile of a player joining with a secret, anonymous players get an empty ID.
+func authenticateProfile(conn *Connection, registry *RoomRegistry, msgType MessageType, name string, secret string) (string, bool) {
+	if secret == "" {
+		return "", true
+	}
+	profileId, err := registry.profiles.Authenticate(name, secret)
+	if err != nil {
+		code := ErrorCode(ErrInvalidCredentials)
+		if errors.Is(err, ErrLoginLocked) {
+			code = ErrTooManyAttempts
+		}
+		SendDirectErrorMessage(
+			conn,
+			*CreateErrorMessage(
+				msgType,
+				code,
+			),
+		)
+		slog.Error("Failed to authenticate profile", "name", name, "err", err)
+		return "", false
+	}
+	return profileId, true
+}
+
+// createRoom opens a room for the connection unless the client or the server has reached its room limit,
+// errors are reported to the connection.
+func createRoom(conn *Connection, registry *RoomRegistry, msgType MessageType) (*Game, bool) {
+	game, err := registry.CreateRoom(conn.RemoteHost())
+	if err != nil {
+		code := ErrorCode(ErrRoomLimitReached)
+		if errors.Is(err, ErrClientRoomLimit) {
+			code = ErrTooManyRooms
+		}
+		SendDirectErrorMessage(
+			conn,
+			*CreateErrorMessage(
+				msgType,
+				code,
+			),
+		)
+		slog.Warn("Failed to create room.", "client", conn.RemoteAddr(), "err", err)
+		return nil, false
+	}
+	return game, true
+}
+
+func gameHistoryHandler(registry *RoomRegistry, w http.ResponseWriter, r *http.Request) {
+	events, err := registry.history.Load(r.PathValue("id"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			http.Error(w, "game not found", http.StatusNotFound)
+			return
+		}
+		slog.Error("Failed to load game history.", "gameId", r.PathValue("id"), "err", err)
+		http.Error(w, "failed to load game history", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(events); err != nil {
+		slog.Error("Failed to write game history response.", "err", err)
+	}
+}
+
+func leaderboardHandler(registry *RoomRegistry, w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(w).Encode(registry.profiles.GetLeaderboard(LeaderboardSize))
+	if err != nil {
+		slog.Error("Failed to write leaderboard response.", "err", err)
+	}
+}
+
 func decodeIncomingMessage(ss *SchemaStorage, data []byte) (MessageBase, error) {
 	var typeMsg TypeProperty
 	if err := json.Unmarshal(data, &typeMsg); err != nil {
@@ -123,6 +435,10 @@ func initStorage() error {
 }
 
 func main() {
+	if len(os.Args) > 1 {
+		// Subcommands load only the storage they need, so they do not depend on decks/.
+		os.Exit(runCommand(os.Args[1:]))
+	}
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
 		Level: slog.LevelDebug,
 	}))
@@ -135,11 +451,100 @@ func main() {
 	if addr == "" {
 		addr = "localhost:8080"
 	}
-	game := CreateGame()
-	go game.run() // TODO: MULTIPLE GAME ROOMS
+	dataDir := os.Getenv("DATA_DIR")
+	if dataDir == "" {
+		dataDir = "data/"
+	}
+	if interval := os.Getenv("PING_INTERVAL"); interval != "" {
+		d, err := time.ParseDuration(interval)
+		if err != nil || d <= 0 {
+			slog.Error("Invalid ping interval, expected a positive duration such as 30s", "interval", interval)
+			os.Exit(1)
+		}
+		pingInterval = d
+	}
+	switch policy := SlowClientPolicy(os.Getenv("SLOW_CLIENT_POLICY")); policy {
+	case "":
+	case DropMessages, DisconnectClient:
+		slowClientPolicy = policy
+	default:
+		slog.Error("Unknown slow client policy, expected drop or disconnect", "policy", policy)
+		os.Exit(1)
+	}
+	snapshots, err := CreateSnapshotStorage(dataDir)
+	if err != nil {
+		slog.Error("Failed to initialize snapshot storage", "err", err)
+		os.Exit(1)
+	}
+	profiles, err := CreateProfileStorage(filepath.Join(dataDir, "profiles"))
+	if err != nil {
+		slog.Error("Failed to initialize profile storage", "err", err)
+		os.Exit(1)
+	}
+	history, err := CreateHistoryStorage(filepath.Join(dataDir, "history"))
+	if err != nil {
+		slog.Error("Failed to initialize history storage", "err", err)
+		os.Exit(1)
+	}
+	registry := CreateRoomRegistry(snapshots, profiles, history)
+	if err := registry.RestoreRooms(); err != nil {
+		slog.Error("Failed to restore rooms from snapshots", "err", err)
+	}
+	go registry.runCleanup(RoomSweepInterval, RoomIdleTimeout)
 	http.Handle("/", http.FileServer(http.Dir("frontend/")))
 	http.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
-		playerConnHandler(game, w, r)
+		playerConnHandler(registry, w, r)
 	})
-	log.Fatal(http.ListenAndServe(addr, nil))
+	http.HandleFunc("GET /api/leaderboard", func(w http.ResponseWriter, r *http.Request) {
+		leaderboardHandler(registry, w, r)
+	})
+	http.HandleFunc("GET /api/games/{id}", func(w http.ResponseWriter, r *http.Request) {
+		gameHistoryHandler(registry, w, r)
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	server := &http.Server{Addr: addr}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatal(err)
+		}
+	}()
+	<-ctx.Done()
+	stop()
+	shutdown(server, registry)
+}
+
+// shutdown stops accepting connections, saves running games and tells clients when to reconnect.
+func shutdown(server *http.Server, registry *RoomRegistry) {
+	slog.Info("Shutting down server.")
+	ctx, cancel := context.WithTimeout(context.Background(), ShutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(ctx); err != nil {
+		slog.Error("Failed to stop HTTP server.", "err", err)
+	}
+	registry.Shutdown()
+
+	shutdownMsg := &ServerShutdownMessage{
+		TypeProperty: TypeProperty{
+			Type: ServerShutdownMsg,
+		},
+		ReconnectAfter: RestartHint,
+	}
+	data, err := json.Marshal(shutdownMsg)
+	if err != nil {
+		slog.Error("Failed to marshal server shutdown message.", "err", err)
+		return
+	}
+	ss, _ := GetSchemaStorage()
+	if err := ss.validate(ServerShutdownMsg, data); err != nil {
+		slog.Error("Failed to validate server shutdown message.", "err", err)
+		return
+	}
+	if err := CloseAllConnections(ctx, data, websocket.CloseServiceRestart, "server restarting"); err != nil {
+		slog.Warn("Not all clients were closed before the shutdown deadline.", "err", err)
+		return
+	}
+	slog.Info("Server stopped.")
 }
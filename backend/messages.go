@@ -6,7 +6,10 @@ type MessageType string
 
 const (
 	// general messages
-	ErrorResponseMsg MessageType = "error_response"
+	ErrorResponseMsg  MessageType = "error_response"
+	ServerShutdownMsg MessageType = "server_shutdown"
+	TimeSyncMsg       MessageType = "time_sync"
+	TimeSyncAckMsg    MessageType = "time_sync_ack"
 	// player connections
 	ConnectMsg            MessageType = "connect"
 	ConnectAckMsg         MessageType = "connect_ack"
@@ -16,29 +19,65 @@ const (
 	PlayerLeftMsg         MessageType = "player_left"
 	PlayerDisconnectedMsg MessageType = "player_disconnected"
 	PlayerReconnectedMsg  MessageType = "player_reconnected"
+	PlayerQueuedMsg       MessageType = "player_queued"
+	SpectateMsg           MessageType = "spectate"
+	SpectatorJoinedMsg    MessageType = "spectator_joined"
+	SpectatorLeftMsg      MessageType = "spectator_left"
+	// game rooms
+	CreateRoomMsg  MessageType = "create_room"
+	RoomCreatedMsg MessageType = "room_created"
+	JoinRoomMsg    MessageType = "join_room"
+	RoomListMsg    MessageType = "room_list"
+	// player profiles
+	GetLeaderboardMsg MessageType = "get_leaderboard"
+	LeaderboardMsg    MessageType = "leaderboard"
+	// game history
+	ReplayGameMsg    MessageType = "replay_game"
+	ReplayStartedMsg MessageType = "replay_started"
+	ReplayEndedMsg   MessageType = "replay_ended"
 	// lobby state
 	PlayerListMsg       MessageType = "player_list"
 	ChangeTeamMsg       MessageType = "change_team"
 	TeamChangedMsg      MessageType = "team_changed"
 	PlayerReadyMsg      MessageType = "player_ready"
+	UpdateSettingsMsg   MessageType = "update_settings"
+	SettingsChangedMsg  MessageType = "settings_changed"
+	HostChangedMsg      MessageType = "host_changed"
+	DeckListMsg         MessageType = "deck_list"
+	UploadWordsMsg      MessageType = "upload_words"
+	WordsUploadedMsg    MessageType = "words_uploaded"
 	GameStateChangedMsg MessageType = "game_state_changed"
+	// lobby moderation
+	KickPlayerMsg    MessageType = "kick_player"
+	PlayerKickedMsg  MessageType = "player_kicked"
+	MovePlayerMsg    MessageType = "move_player"
+	TransferHostMsg  MessageType = "transfer_host"
+	StartGameMsg     MessageType = "start_game"
+	ShuffleTeamsMsg  MessageType = "shuffle_teams"
+	TeamsShuffledMsg MessageType = "teams_shuffled"
 	// game rounds
-	RoundSetupMsg   MessageType = "round_setup"
-	StartRoundMsg   MessageType = "start_round"
-	RoundStartedMsg MessageType = "round_started"
-	RoundEndedMsg   MessageType = "round_ended"
-	PauseRoundMsg   MessageType = "pause_round"
-	RoundPausedMsg  MessageType = "round_paused"
-	ResumeRoundMsg  MessageType = "resume_round"
-	RoundResumedMsg MessageType = "round_resumed"
-	GameEndedMsg    MessageType = "game_ended"
-	ResetGameMsg    MessageType = "reset_game"
-	GameResetMsg    MessageType = "game_reset"
+	RoundSetupMsg     MessageType = "round_setup"
+	StartRoundMsg     MessageType = "start_round"
+	RoundStartedMsg   MessageType = "round_started"
+	RoundEndedMsg     MessageType = "round_ended"
+	PauseRoundMsg     MessageType = "pause_round"
+	RoundPausedMsg    MessageType = "round_paused"
+	ResumeRoundMsg    MessageType = "resume_round"
+	RoundResumedMsg   MessageType = "round_resumed"
+	PauseCountdownMsg MessageType = "pause_countdown"
+	TimerTickMsg      MessageType = "timer_tick"
+	RoundSummaryMsg   MessageType = "round_summary"
+	GameEndedMsg      MessageType = "game_ended"
+	GameSummaryMsg    MessageType = "game_summary"
+	ResetGameMsg      MessageType = "reset_game"
+	GameResetMsg      MessageType = "game_reset"
 	// round actions
 	SkipWordMsg    MessageType = "skip_word"
 	WordSkippedMsg MessageType = "word_skipped"
 	GuessWordMsg   MessageType = "guess_word"
 	WordGuessedMsg MessageType = "word_guessed"
+	BuzzMsg        MessageType = "buzz"
+	WordBuzzedMsg  MessageType = "word_buzzed"
 	WordListMsg    MessageType = "word_list"
 )
 
@@ -62,6 +101,32 @@ func (prop PlayerIdProperty) GetPlayerId() string {
 	return prop.PlayerId
 }
 
+type TimingProperty struct {
+	// Unix time of the server in milliseconds when the message was created
+	ServerTime int64 `json:"serverTime"`
+	// Time left in the round in milliseconds
+	RemainingTime int64 `json:"remainingTime"`
+}
+
+// SchemaMessage is implemented by messages validated against a schema other than their type.
+type SchemaMessage interface {
+	MessageBase
+	GetSchemaId() MessageType
+}
+
+// RedactableMessage is implemented by messages carrying words hidden from guessers.
+type RedactableMessage interface {
+	MessageBase
+	Redact() MessageBase
+}
+
+func GetSchemaId(msg MessageBase) MessageType {
+	if schemaMsg, ok := msg.(SchemaMessage); ok {
+		return schemaMsg.GetSchemaId()
+	}
+	return msg.GetType()
+}
+
 type PlayerMessage interface {
 	MessageBase
 	GetPlayerId() string
@@ -74,9 +139,34 @@ type ErrorResponseMessage struct {
 	ErrorCode  ErrorCode   `json:"errorCode"`
 }
 
+type ServerShutdownMessage struct {
+	TypeProperty
+	// Seconds after which clients should try to reconnect
+	ReconnectAfter int `json:"reconnectAfter"`
+}
+
+type TimeSyncMessage struct {
+	TypeProperty
+	// Unix time of the client in milliseconds when the request was sent
+	ClientTime int64 `json:"clientTime"`
+}
+
+type TimeSyncAckMessage struct {
+	TypeProperty
+	// Client time copied from the request
+	ClientTime int64 `json:"clientTime"`
+	// Unix time of the server in milliseconds when the request was received
+	ServerReceiveTime int64 `json:"serverReceiveTime"`
+	// Unix time of the server in milliseconds when the response was sent
+	ServerSendTime int64 `json:"serverSendTime"`
+}
+
 type ConnectMessage struct {
 	TypeProperty
-	Name string `json:"name"`
+	Name     string `json:"name"`
+	RoomCode string `json:"roomCode"`
+	// Optional profile secret, joins as a registered player when set
+	Secret string `json:"secret"`
 }
 
 type ConnectAckMessage struct {
@@ -84,25 +174,98 @@ type ConnectAckMessage struct {
 	PlayerIdProperty
 	SessionToken string `json:"sessionToken"`
 	Name         string `json:"name"`
+	RoomCode     string `json:"roomCode"`
+	// Is the connection a spectator that cannot take part in the game
+	Spectator bool `json:"spectator,omitempty"`
 }
 
 type ReconnectMessage struct {
 	TypeProperty
 	PlayerId     string `json:"playerId"`
 	SessionToken string `json:"sessionToken"`
+	RoomCode     string `json:"roomCode"`
 }
 
 type ReconnectAckMessage struct {
 	ConnectAckMessage
 	Team              Team         `json:"team"`
 	State             GameState    `json:"state"`
+	Settings          GameSettings `json:"settings"`
 	RemainingDuration int          `json:"remainingDuration"`
-	CurrentTeam       Team         `json:"currentTeam"`
-	GuesserId         string       `json:"guesserId"`
-	HintGiverId       string       `json:"hintGiverId"`
-	RedScore          int          `json:"redScore"`
-	BlueScore         int          `json:"blueScore"`
-	Words             []*TabooWord `json:"words"`
+	TimingProperty
+	CurrentTeam Team         `json:"currentTeam"`
+	GuesserIds  []string     `json:"guesserIds"`
+	HintGiverId string       `json:"hintGiverId"`
+	Scores      map[Team]int `json:"scores"`
+	Words       []*TabooWord `json:"words"`
+}
+
+// RedactedReconnectAckMessage is the reconnect ack sent to guessers.
+type RedactedReconnectAckMessage struct {
+	ReconnectAckMessage
+	Words []RedactedWord `json:"words"`
+}
+
+func (m ReconnectAckMessage) Redact() MessageBase {
+	words := m.Words
+	m.Words = nil
+	return &RedactedReconnectAckMessage{
+		ReconnectAckMessage: m,
+		Words:               RedactWords(words),
+	}
+}
+
+func (m RedactedReconnectAckMessage) GetSchemaId() MessageType {
+	return "reconnect_ack_redacted"
+}
+
+type CreateRoomMessage struct {
+	TypeProperty
+}
+
+type RoomCreatedMessage struct {
+	TypeProperty
+	RoomCode string `json:"roomCode"`
+}
+
+type JoinRoomMessage struct {
+	TypeProperty
+	Name     string `json:"name"`
+	RoomCode string `json:"roomCode"`
+	// Optional profile secret, joins as a registered player when set
+	Secret string `json:"secret"`
+}
+
+type RoomListMessage struct {
+	TypeProperty
+	Rooms []RoomInfo `json:"rooms,omitempty"`
+}
+
+type GetLeaderboardMessage struct {
+	TypeProperty
+}
+
+type LeaderboardMessage struct {
+	TypeProperty
+	Players []LeaderboardEntry `json:"players"`
+}
+
+type ReplayGameMessage struct {
+	TypeProperty
+	GameId string `json:"gameId"`
+	// Replay speed multiplier
+	Speed int `json:"speed"`
+}
+
+type ReplayStartedMessage struct {
+	TypeProperty
+	GameId string `json:"gameId"`
+	Speed  int    `json:"speed"`
+}
+
+type ReplayEndedMessage struct {
+	TypeProperty
+	GameId string `json:"gameId"`
 }
 
 type PlayerJoinedMessage struct {
@@ -116,6 +279,23 @@ type PlayerLeftMessage struct {
 	PlayerIdProperty
 }
 
+type SpectateMessage struct {
+	TypeProperty
+	Name     string `json:"name"`
+	RoomCode string `json:"roomCode"`
+}
+
+type SpectatorJoinedMessage struct {
+	TypeProperty
+	PlayerIdProperty
+	Name string `json:"name"`
+}
+
+type SpectatorLeftMessage struct {
+	TypeProperty
+	PlayerIdProperty
+}
+
 type PlayerDisconnectedMessage struct {
 	TypeProperty
 	PlayerIdProperty
@@ -128,7 +308,13 @@ type PlayerReconnectedMessage struct {
 
 type PlayerListMessage struct {
 	TypeProperty
-	Players []PlayerInfo `json:"players"`
+	HostId     string          `json:"hostId"`
+	Settings   GameSettings    `json:"settings"`
+	Teams      []TeamInfo      `json:"teams"`
+	Players    []PlayerInfo    `json:"players"`
+	Spectators []SpectatorInfo `json:"spectators"`
+	// Players waiting to join a team at the next round boundary
+	Queue []QueuedPlayer `json:"queue"`
 }
 
 type ChangeTeamMessage struct {
@@ -143,12 +329,104 @@ type TeamChangedMessage struct {
 	Team Team `json:"team"`
 }
 
+type PlayerQueuedMessage struct {
+	TypeProperty
+	PlayerIdProperty
+	// Preferred team, unassigned if the player has no preference
+	Team Team `json:"team"`
+	// Position in the join queue
+	Position int `json:"position"`
+}
+
 type PlayerReadyMessage struct {
 	TypeProperty
 	PlayerIdProperty
 	IsReady bool `json:"isReady"`
 }
 
+type UpdateSettingsMessage struct {
+	TypeProperty
+	PlayerIdProperty
+	Settings GameSettings `json:"settings"`
+}
+
+type SettingsChangedMessage struct {
+	TypeProperty
+	Settings GameSettings `json:"settings"`
+	Teams    []TeamInfo   `json:"teams"`
+}
+
+type DeckListMessage struct {
+	TypeProperty
+	PlayerIdProperty
+	Decks []DeckInfo `json:"decks,omitempty"`
+}
+
+type UploadWordsMessage struct {
+	TypeProperty
+	PlayerIdProperty
+	Title  string         `json:"title"`
+	Format WordListFormat `json:"format"`
+	Data   string         `json:"data"`
+}
+
+type WordsUploadedMessage struct {
+	TypeProperty
+	PlayerIdProperty
+	Deck DeckInfo `json:"deck"`
+}
+
+type HostChangedMessage struct {
+	TypeProperty
+	PlayerIdProperty
+}
+
+type KickPlayerMessage struct {
+	TypeProperty
+	PlayerIdProperty
+	// ID of the player to remove from the room
+	TargetId string `json:"targetId"`
+}
+
+type PlayerKickedMessage struct {
+	TypeProperty
+	PlayerIdProperty
+}
+
+type MovePlayerMessage struct {
+	TypeProperty
+	PlayerIdProperty
+	// ID of the player to move
+	TargetId string `json:"targetId"`
+	Team     Team   `json:"team"`
+}
+
+type TransferHostMessage struct {
+	TypeProperty
+	PlayerIdProperty
+	// ID of the new host
+	TargetId string `json:"targetId"`
+}
+
+type StartGameMessage struct {
+	TypeProperty
+	PlayerIdProperty
+}
+
+type ShuffleTeamsMessage struct {
+	TypeProperty
+	PlayerIdProperty
+	Mode ShuffleMode `json:"mode"`
+}
+
+type TeamsShuffledMessage struct {
+	TypeProperty
+	// Mode used for the shuffle, balanced falls back to random without player statistics
+	Mode ShuffleMode `json:"mode"`
+	// Team of every player after the shuffle
+	Players []TeamAssignment `json:"players"`
+}
+
 type GameStateChangedMessage struct {
 	TypeProperty
 	State GameState `json:"state"`
@@ -159,6 +437,25 @@ type WordListMessage struct {
 	Words []*TabooWord `json:"words"`
 }
 
+// RedactedWordListMessage is the word list sent to guessers.
+type RedactedWordListMessage struct {
+	WordListMessage
+	Words []RedactedWord `json:"words"`
+}
+
+func (m WordListMessage) Redact() MessageBase {
+	words := m.Words
+	m.Words = nil
+	return &RedactedWordListMessage{
+		WordListMessage: m,
+		Words:           RedactWords(words),
+	}
+}
+
+func (m RedactedWordListMessage) GetSchemaId() MessageType {
+	return "word_list_redacted"
+}
+
 type SkipWordMessage struct {
 	TypeProperty
 	PlayerIdProperty
@@ -167,6 +464,7 @@ type SkipWordMessage struct {
 type WordSkippedMessage struct {
 	TypeProperty
 	PlayerIdProperty
+	Scores map[Team]int `json:"scores"`
 }
 
 type GuessWordMessage struct {
@@ -177,19 +475,65 @@ type GuessWordMessage struct {
 type WordGuessedMessage struct {
 	TypeProperty
 	PlayerIdProperty
-	RedScore  int `json:"redScore"`
-	BlueScore int `json:"blueScore"`
+	Scores map[Team]int `json:"scores"`
+}
+
+type RoundSummaryMessage struct {
+	TypeProperty
+	RoundSummary
+}
+
+type GameSummaryMessage struct {
+	TypeProperty
+	// ID of the game log, empty if history is disabled
+	GameId  string         `json:"gameId,omitempty"`
+	Scores  map[Team]int   `json:"scores"`
+	Winners []Team         `json:"winners"`
+	Rounds  []RoundSummary `json:"rounds"`
+	Players []PlayerStats  `json:"players"`
+}
+
+type BuzzMessage struct {
+	TypeProperty
+	PlayerIdProperty
+	// ID of the word the buzz is about
+	WordId uint `json:"wordId"`
+}
+
+type WordBuzzedMessage struct {
+	TypeProperty
+	PlayerIdProperty
+	Scores map[Team]int `json:"scores"`
 }
 
 type RoundSetupMessage struct {
 	TypeProperty
 	Team        Team         `json:"team"`
-	GuesserId   string       `json:"guesserId"`
+	GuesserIds  []string     `json:"guesserIds"`
 	HintGiverId string       `json:"hintGiverId"`
 	Duration    int          `json:"duration"`
 	Words       []*TabooWord `json:"words"`
 }
 
+// RedactedRoundSetupMessage is the round setup sent to guessers.
+type RedactedRoundSetupMessage struct {
+	RoundSetupMessage
+	Words []RedactedWord `json:"words"`
+}
+
+func (m RoundSetupMessage) Redact() MessageBase {
+	words := m.Words
+	m.Words = nil
+	return &RedactedRoundSetupMessage{
+		RoundSetupMessage: m,
+		Words:             RedactWords(words),
+	}
+}
+
+func (m RedactedRoundSetupMessage) GetSchemaId() MessageType {
+	return "round_setup_redacted"
+}
+
 type StartRoundMessage struct {
 	TypeProperty
 	PlayerIdProperty
@@ -198,6 +542,7 @@ type StartRoundMessage struct {
 type RoundStartedMessage struct {
 	TypeProperty
 	PlayerIdProperty
+	TimingProperty
 }
 
 type RoundEndedMessage struct {
@@ -207,6 +552,20 @@ type RoundEndedMessage struct {
 type RoundPausedMessage struct {
 	TypeProperty
 	RemainingDuration int `json:"remainingDuration"`
+	TimingProperty
+}
+
+type PauseRoundMessage struct {
+	TypeProperty
+	PlayerIdProperty
+}
+
+type PauseCountdownMessage struct {
+	TypeProperty
+	// Action taken once the countdown ends
+	Action PauseAction `json:"action"`
+	// Seconds until the action is taken
+	Duration int `json:"duration"`
 }
 
 type ResumeRoundMessage struct {
@@ -217,12 +576,17 @@ type ResumeRoundMessage struct {
 type RoundResumedMessage struct {
 	TypeProperty
 	PlayerIdProperty
+	TimingProperty
+}
+
+type TimerTickMessage struct {
+	TypeProperty
+	TimingProperty
 }
 
 type GameEndedMessage struct {
 	TypeProperty
-	RedScore  int `json:"redScore"`
-	BlueScore int `json:"blueScore"`
+	Scores map[Team]int `json:"scores"`
 }
 
 type ResetGameMessage struct {
@@ -237,20 +601,54 @@ type GameResetMessage struct {
 
 func ConstructMessageContainer(messageType MessageType) (MessageBase, error) {
 	switch messageType {
+	case TimeSyncMsg:
+		return &TimeSyncMessage{}, nil
 	case ConnectMsg:
 		return &ConnectMessage{}, nil
 	case ReconnectMsg:
 		return &ReconnectMessage{}, nil
+	case CreateRoomMsg:
+		return &CreateRoomMessage{}, nil
+	case JoinRoomMsg:
+		return &JoinRoomMessage{}, nil
+	case SpectateMsg:
+		return &SpectateMessage{}, nil
+	case RoomListMsg:
+		return &RoomListMessage{}, nil
+	case GetLeaderboardMsg:
+		return &GetLeaderboardMessage{}, nil
+	case ReplayGameMsg:
+		return &ReplayGameMessage{}, nil
 	case ChangeTeamMsg:
 		return &ChangeTeamMessage{}, nil
 	case PlayerReadyMsg:
 		return &PlayerReadyMessage{}, nil
+	case UpdateSettingsMsg:
+		return &UpdateSettingsMessage{}, nil
+	case DeckListMsg:
+		return &DeckListMessage{}, nil
+	case UploadWordsMsg:
+		return &UploadWordsMessage{}, nil
+	case KickPlayerMsg:
+		return &KickPlayerMessage{}, nil
+	case MovePlayerMsg:
+		return &MovePlayerMessage{}, nil
+	case TransferHostMsg:
+		return &TransferHostMessage{}, nil
+	case StartGameMsg:
+		return &StartGameMessage{}, nil
+	case ShuffleTeamsMsg:
+		return &ShuffleTeamsMessage{}, nil
 	case StartRoundMsg:
 		return &StartRoundMessage{}, nil
 	case SkipWordMsg:
 		return &SkipWordMessage{}, nil
 	case GuessWordMsg:
 		return &GuessWordMessage{}, nil
+	case BuzzMsg:
+		return &BuzzMessage{}, nil
+	case PauseRoundMsg:
+		return &PauseRoundMessage{}, nil
 	case ResumeRoundMsg:
 		return &ResumeRoundMessage{}, nil
 	case ResetGameMsg:
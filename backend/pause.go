@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Seconds between everyone reconnecting and the round resuming automatically
+const ResumeCountdown = 3
+
+type PauseAction string
+
+const (
+	ResumePauseAction  PauseAction = "resume"
+	ReplacePauseAction PauseAction = "replace"
+	ForfeitPauseAction PauseAction = "forfeit"
+)
+
+func (g *Game) CancelPauseTimer() {
+	if g.pauseCancel != nil {
+		g.pauseCancel()
+		g.pauseCancel = nil
+	}
+	g.pauseAction = ""
+}
+
+// schedulePausePolicyUnlocked starts the countdown of the pause policy for a round paused by a disconnect,
+// returning the countdown message to broadcast or nil if nothing was scheduled.
+func (g *Game) schedulePausePolicyUnlocked() *PauseCountdownMessage {
+	if g.gameState != Paused || !g.autoPaused || g.settings.PausePolicy == ManualPausePolicy {
+		return nil
+	}
+
+	var action PauseAction
+	var duration int
+	switch {
+	case g.AllConnected():
+		action = ResumePauseAction
+		duration = ResumeCountdown
+	case g.settings.PausePolicy == AutoResumePolicy:
+		// someone left again before the round resumed
+		g.CancelPauseTimer()
+		return nil
+	case g.settings.PausePolicy == ReplacePausePolicy:
+		action = ReplacePauseAction
+		duration = g.settings.GraceTimeout
+	default:
+		action = ForfeitPauseAction
+		duration = g.settings.GraceTimeout
+	}
+	if g.pauseAction == action {
+		// the grace period runs from the first disconnect
+		return nil
+	}
+
+	g.CancelPauseTimer()
+	var ctx context.Context
+	ctx, g.pauseCancel = context.WithCancel(context.Background())
+	g.pauseAction = action
+	go func(ctx context.Context) {
+		select {
+		case <-time.After(time.Duration(duration) * time.Second):
+			g.applyPauseAction(ctx, action)
+		case <-ctx.Done():
+		}
+	}(ctx)
+
+	slog.Info("Pause countdown started.", "roomCode", g.code, "action", action, "duration", duration)
+	return &PauseCountdownMessage{
+		TypeProperty: TypeProperty{
+			Type: PauseCountdownMsg,
+		},
+		Action:   action,
+		Duration: duration,
+	}
+}
+
+func (g *Game) applyPauseAction(ctx context.Context, action PauseAction) {
+	g.playerMtx.Lock()
+	// the countdown may have been cancelled while waiting for the lock
+	if ctx.Err() != nil || g.closed || g.gameState != Paused {
+		g.playerMtx.Unlock()
+		return
+	}
+	g.pauseCancel = nil
+	g.pauseAction = ""
+
+	switch action {
+	case ResumePauseAction:
+		if g.AllConnected() {
+			g.resumeRoundUnlocked()
+		}
+	case ReplacePauseAction:
+		if g.continueWithoutMissingUnlocked() {
+			g.resumeRoundUnlocked()
+		} else {
+			slog.Info("Not enough players left in the team, forfeiting round.", "roomCode", g.code)
+			g.gameState = InRound
+			g.endRoundLocked()
+			return
+		}
+	case ForfeitPauseAction:
+		g.gameState = InRound
+		g.endRoundLocked()
+		return
+	}
+	g.playerMtx.Unlock()
+	g.persist()
+}
+
+// continueWithoutMissingUnlocked drops disconnected players from the current round, promoting a guesser
+// if the hint giver is missing. Returns false if the team no longer has enough players. Later rounds leave
+// out missing players as well until they reconnect.
+func (g *Game) continueWithoutMissingUnlocked() bool {
+	round := g.currentRound
+	connected := g.isConnectedUnlocked
+
+	guesserIds := make([]string, 0, len(round.GuesserIds))
+	for _, id := range round.GuesserIds {
+		if connected(id) {
+			guesserIds = append(guesserIds, id)
+		}
+	}
+	hintGiverId := round.HintGiverId
+	if !connected(hintGiverId) && len(guesserIds) > 0 {
+		hintGiverId = guesserIds[0]
+		guesserIds = guesserIds[1:]
+	}
+	if !connected(hintGiverId) || len(guesserIds) == 0 {
+		return false
+	}
+	if hintGiverId == round.HintGiverId && len(guesserIds) == len(round.GuesserIds) {
+		return true
+	}
+
+	if hintGiverId != round.HintGiverId {
+		g.hintsGiven[hintGiverId]++
+	}
+	round.HintGiverId = hintGiverId
+	round.GuesserIds = guesserIds
+
+	// a promoted hint giver needs to see the remaining words
+	setupMsg := round.CreateRoundSetupMessage()
+	setupMsg.Words = g.PreparePendingWordBatch()
+	if err := g.BroadcastWordsUnlocked(g.GetPlayersCopyUnlocked(), setupMsg); err != nil {
+		slog.Error("Failed to broadcast round setup message", "err", err)
+	}
+	return true
+}
@@ -24,7 +24,7 @@ type Player struct {
 	// Player ID
 	id string
 	// client websocket connection
-	conn *websocket.Conn
+	conn *Connection
 	// Session token
 	sessionToken string
 	// Player name
@@ -35,9 +35,11 @@ type Player struct {
 	team Team
 	// Player connected status
 	connected bool
+	// Persistent profile ID, empty for anonymous players
+	profileId string
 }
 
-func (p *Player) SetConnection(conn *websocket.Conn) {
+func (p *Player) SetConnection(conn *Connection) {
 	p.conn = conn
 }
 
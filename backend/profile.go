@@ -0,0 +1,335 @@
+package main
+
+import (
+	"cmp"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const LeaderboardSize = 20
+
+// PBKDF2 iterations for new profile secrets, stored with each profile so it can be raised later
+const SecretHashIterations = 600_000
+
+// Failed attempts for a profile name before further attempts are refused
+const MaxFailedLogins = 5
+
+// Time attempts for a profile name are refused after too many failures
+const LoginLockout = time.Minute
+
+var (
+	ErrSecretMismatch = errors.New("profile secret does not match")
+	ErrLoginLocked    = errors.New("too many failed attempts for profile")
+)
+
+type ProfileStats struct {
+	GamesPlayed    int `json:"gamesPlayed"`
+	Wins           int `json:"wins"`
+	WordsDescribed int `json:"wordsDescribed"`
+	WordsGuessed   int `json:"wordsGuessed"`
+	// Total time in milliseconds spent on described words that were guessed
+	WordTime int64 `json:"wordTime"`
+}
+
+type Profile struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+	// Hex encoded random salt of the secret hash
+	Salt string `json:"salt"`
+	// Hex encoded PBKDF2-SHA256 key derived from the secret
+	SecretHash string `json:"secretHash"`
+	// PBKDF2 iterations used for the secret hash
+	Iterations int          `json:"iterations"`
+	Stats      ProfileStats `json:"stats"`
+	CreatedAt  time.Time    `json:"createdAt"`
+}
+
+type LeaderboardEntry struct {
+	Name            string `json:"name"`
+	GamesPlayed     int    `json:"gamesPlayed"`
+	Wins            int    `json:"wins"`
+	WordsDescribed  int    `json:"wordsDescribed"`
+	WordsGuessed    int    `json:"wordsGuessed"`
+	AverageWordTime int64  `json:"averageWordTime"`
+}
+
+type ProfileStorage struct {
+	// File with all profiles
+	file string
+	// Profile mutex
+	profileMtx sync.RWMutex
+	// Profiles by ID
+	profiles map[string]*Profile
+	// Failed login mutex
+	loginMtx sync.Mutex
+	// Failed login attempts by lower case profile name
+	failedLogins map[string]*failedLogin
+}
+
+type failedLogin struct {
+	count int
+	// Attempts are refused until this time
+	lockedUntil time.Time
+}
+
+func CreateProfileStorage(dir string) (*ProfileStorage, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create profile dir %s: %w", dir, err)
+	}
+	ps := &ProfileStorage{
+		file:         filepath.Join(dir, "profiles.json"),
+		profileMtx:   sync.RWMutex{},
+		profiles:     make(map[string]*Profile),
+		loginMtx:     sync.Mutex{},
+		failedLogins: make(map[string]*failedLogin),
+	}
+
+	data, err := os.ReadFile(ps.file)
+	if os.IsNotExist(err) {
+		return ps, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profiles file %s: %w", ps.file, err)
+	}
+
+	var profiles []*Profile
+	if err := json.Unmarshal(data, &profiles); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal profiles file %s: %w", ps.file, err)
+	}
+	for _, profile := range profiles {
+		ps.profiles[profile.Id] = profile
+	}
+	return ps, nil
+}
+
+// Authenticate returns the ID of the profile with the given name, creating it if it does not exist yet.
+func (ps *ProfileStorage) Authenticate(name string, secret string) (string, error) {
+	if err := ps.checkLoginAllowed(name); err != nil {
+		return "", err
+	}
+
+	// hashing is slow on purpose, do not hold the lock meanwhile
+	ps.profileMtx.RLock()
+	existing := ps.findByNameUnlocked(name)
+	var profile Profile
+	if existing != nil {
+		profile = *existing
+	}
+	ps.profileMtx.RUnlock()
+
+	if existing != nil {
+		hash, err := hashSecret(profile.Salt, secret, profile.Iterations)
+		if err != nil {
+			return "", err
+		}
+		if subtle.ConstantTimeCompare([]byte(hash), []byte(profile.SecretHash)) != 1 {
+			ps.recordFailedLogin(name)
+			return "", ErrSecretMismatch
+		}
+		ps.clearFailedLogins(name)
+		return profile.Id, nil
+	}
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to generate profile salt: %w", err)
+	}
+	profile = Profile{
+		Id:         generateUUID(),
+		Name:       name,
+		Salt:       hex.EncodeToString(salt),
+		SecretHash: "",
+		Iterations: SecretHashIterations,
+		CreatedAt:  time.Now(),
+	}
+	hash, err := hashSecret(profile.Salt, secret, profile.Iterations)
+	if err != nil {
+		return "", err
+	}
+	profile.SecretHash = hash
+
+	ps.profileMtx.Lock()
+	defer ps.profileMtx.Unlock()
+	if ps.findByNameUnlocked(name) != nil {
+		// another player claimed the name while the secret was hashed
+		return "", ErrSecretMismatch
+	}
+	ps.profiles[profile.Id] = &profile
+	if err := ps.saveUnlocked(); err != nil {
+		delete(ps.profiles, profile.Id)
+		return "", err
+	}
+	return profile.Id, nil
+}
+
+func (ps *ProfileStorage) findByNameUnlocked(name string) *Profile {
+	for _, profile := range ps.profiles {
+		if strings.EqualFold(profile.Name, name) {
+			return profile
+		}
+	}
+	return nil
+}
+
+func (ps *ProfileStorage) checkLoginAllowed(name string) error {
+	ps.loginMtx.Lock()
+	defer ps.loginMtx.Unlock()
+
+	failed, exists := ps.failedLogins[strings.ToLower(name)]
+	if exists && time.Now().Before(failed.lockedUntil) {
+		return ErrLoginLocked
+	}
+	return nil
+}
+
+// recordFailedLogin counts a wrong secret, refusing attempts for the name for a while after too many.
+func (ps *ProfileStorage) recordFailedLogin(name string) {
+	ps.loginMtx.Lock()
+	defer ps.loginMtx.Unlock()
+
+	key := strings.ToLower(name)
+	failed, exists := ps.failedLogins[key]
+	if !exists {
+		failed = &failedLogin{}
+		ps.failedLogins[key] = failed
+	}
+	failed.count++
+	if failed.count >= MaxFailedLogins {
+		failed.count = 0
+		failed.lockedUntil = time.Now().Add(LoginLockout)
+	}
+}
+
+func (ps *ProfileStorage) clearFailedLogins(name string) {
+	ps.loginMtx.Lock()
+	defer ps.loginMtx.Unlock()
+
+	delete(ps.failedLogins, strings.ToLower(name))
+}
+
+// RecordGame adds statistics of a finished game to the profiles of its players.
+func (ps *ProfileStorage) RecordGame(results map[string]ProfileStats) error {
+	ps.profileMtx.Lock()
+	defer ps.profileMtx.Unlock()
+
+	for profileId, result := range results {
+		profile, exists := ps.profiles[profileId]
+		if !exists {
+			continue
+		}
+		profile.Stats.GamesPlayed += result.GamesPlayed
+		profile.Stats.Wins += result.Wins
+		profile.Stats.WordsDescribed += result.WordsDescribed
+		profile.Stats.WordsGuessed += result.WordsGuessed
+		profile.Stats.WordTime += result.WordTime
+	}
+	return ps.saveUnlocked()
+}
+
+// GetStats returns the statistics of the profile, false if the profile does not exist.
+func (ps *ProfileStorage) GetStats(profileId string) (ProfileStats, bool) {
+	ps.profileMtx.RLock()
+	defer ps.profileMtx.RUnlock()
+
+	profile, exists := ps.profiles[profileId]
+	if !exists {
+		return ProfileStats{}, false
+	}
+	return profile.Stats, true
+}
+
+// GetLeaderboard returns profiles with the most wins, then most described words.
+func (ps *ProfileStorage) GetLeaderboard(limit int) []LeaderboardEntry {
+	ps.profileMtx.RLock()
+	defer ps.profileMtx.RUnlock()
+
+	entries := make([]LeaderboardEntry, 0, len(ps.profiles))
+	for _, profile := range ps.profiles {
+		if profile.Stats.GamesPlayed == 0 {
+			continue
+		}
+		entries = append(entries, profile.CreateLeaderboardEntry())
+	}
+	slices.SortFunc(entries, func(a, b LeaderboardEntry) int {
+		return cmp.Or(
+			cmp.Compare(b.Wins, a.Wins),
+			cmp.Compare(b.WordsDescribed, a.WordsDescribed),
+			strings.Compare(a.Name, b.Name),
+		)
+	})
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries
+}
+
+func (ps *ProfileStorage) saveUnlocked() error {
+	profiles := make([]*Profile, 0, len(ps.profiles))
+	for _, profile := range ps.profiles {
+		profiles = append(profiles, profile)
+	}
+	slices.SortFunc(profiles, func(a, b *Profile) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	data, err := json.Marshal(profiles)
+	if err != nil {
+		return fmt.Errorf("failed to marshal profiles: %w", err)
+	}
+	// write to a temporary file first so a crash never leaves a truncated file
+	tmp := ps.file + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write profiles file: %w", err)
+	}
+	if err := os.Rename(tmp, ps.file); err != nil {
+		return fmt.Errorf("failed to replace profiles file: %w", err)
+	}
+	return nil
+}
+
+func (p Profile) CreateLeaderboardEntry() LeaderboardEntry {
+	entry := LeaderboardEntry{
+		Name:           p.Name,
+		GamesPlayed:    p.Stats.GamesPlayed,
+		Wins:           p.Stats.Wins,
+		WordsDescribed: p.Stats.WordsDescribed,
+		WordsGuessed:   p.Stats.WordsGuessed,
+	}
+	if p.Stats.WordsDescribed > 0 {
+		entry.AverageWordTime = p.Stats.WordTime / int64(p.Stats.WordsDescribed)
+	}
+	return entry
+}
+
+// Skill rates a player by words described and guessed per game, weighted by their win rate.
+func (s ProfileStats) Skill() float64 {
+	if s.GamesPlayed == 0 {
+		return 0
+	}
+	games := float64(s.GamesPlayed)
+	return float64(s.WordsDescribed+s.WordsGuessed) / games * (1 + float64(s.Wins)/games)
+}
+
+func hashSecret(salt string, secret string, iterations int) (string, error) {
+	saltBytes, err := hex.DecodeString(salt)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode profile salt: %w", err)
+	}
+	key, err := pbkdf2.Key(sha256.New, secret, saltBytes, iterations, sha256.Size)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash profile secret: %w", err)
+	}
+	return hex.EncodeToString(key), nil
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"errors"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const RoomCodeLength = 5
+const RoomIdleTimeout = 10 * time.Minute
+const RoomSweepInterval = time.Minute
+
+// Time after which a room nobody joined is removed
+const RoomUnusedTimeout = time.Minute
+
+// Most rooms open on the server at once
+const MaxRooms = 100
+
+// Most open rooms created from a single client address
+const MaxRoomsPerClient = 3
+
+var (
+	ErrServerRoomLimit = errors.New("server has too many open rooms")
+	ErrClientRoomLimit = errors.New("client has too many open rooms")
+)
+
+// Longest time the server waits for clients to be closed on shutdown
+const ShutdownTimeout = 10 * time.Second
+
+// Seconds after which clients are told to reconnect when the server restarts
+const RestartHint = 5
+
+type RoomInfo struct {
+	RoomCode    string    `json:"roomCode"`
+	PlayerCount int       `json:"playerCount"`
+	MaxPlayers  int       `json:"maxPlayers"`
+	State       GameState `json:"state"`
+}
+
+type RoomRegistry struct {
+	// Room mutex
+	roomMtx sync.RWMutex
+	// Active game rooms by room code
+	rooms map[string]*Game
+	// Client address each room was created from by room code
+	creators map[string]string
+	// Storage for game snapshots shared by all rooms
+	snapshots *SnapshotStorage
+	// Storage for player profiles shared by all rooms
+	profiles *ProfileStorage
+	// Storage for game event logs shared by all rooms
+	history *HistoryStorage
+}
+
+func CreateRoomRegistry(snapshots *SnapshotStorage, profiles *ProfileStorage, history *HistoryStorage) *RoomRegistry {
+	return &RoomRegistry{
+		roomMtx:   sync.RWMutex{},
+		rooms:     make(map[string]*Game),
+		creators:  make(map[string]string),
+		snapshots: snapshots,
+		profiles:  profiles,
+		history:   history,
+	}
+}
+
+// CreateRoom opens a new room for the client address unless the server or the client has reached its room limit.
+func (rr *RoomRegistry) CreateRoom(creator string) (*Game, error) {
+	rr.roomMtx.Lock()
+	defer rr.roomMtx.Unlock()
+
+	if len(rr.rooms) >= MaxRooms {
+		return nil, ErrServerRoomLimit
+	}
+	created := 0
+	for _, address := range rr.creators {
+		if address == creator {
+			created++
+		}
+	}
+	if created >= MaxRoomsPerClient {
+		return nil, ErrClientRoomLimit
+	}
+
+	code := generateRoomCode(RoomCodeLength)
+	for {
+		if _, exists := rr.rooms[code]; !exists {
+			break
+		}
+		code = generateRoomCode(RoomCodeLength)
+	}
+
+	game := CreateGame(code)
+	game.snapshots = rr.snapshots
+	game.profiles = rr.profiles
+	game.history = rr.history.CreateLog()
+	rr.rooms[code] = game
+	rr.creators[code] = creator
+	go game.run()
+
+	slog.Info("Room created.", "roomCode", code)
+	return game, nil
+}
+
+func (rr *RoomRegistry) RestoreRooms() error {
+	snapshots, err := rr.snapshots.LoadAll()
+	if err != nil {
+		return err
+	}
+
+	rr.roomMtx.Lock()
+	defer rr.roomMtx.Unlock()
+
+	for _, snapshot := range snapshots {
+		if _, exists := rr.rooms[snapshot.Code]; exists {
+			continue
+		}
+		game := RestoreGame(snapshot)
+		game.snapshots = rr.snapshots
+		game.profiles = rr.profiles
+		if snapshot.HistoryId != "" {
+			game.history = rr.history.ResumeLog(snapshot.HistoryId)
+		} else {
+			game.history = rr.history.CreateLog()
+		}
+		rr.rooms[snapshot.Code] = game
+		go game.run()
+		slog.Info("Room restored from snapshot.", "roomCode", snapshot.Code, "players", len(snapshot.Players))
+	}
+	return nil
+}
+
+func (rr *RoomRegistry) GetRoom(code string) (*Game, bool) {
+	rr.roomMtx.RLock()
+	defer rr.roomMtx.RUnlock()
+
+	game, exists := rr.rooms[code]
+	return game, exists
+}
+
+func (rr *RoomRegistry) ListRooms() []RoomInfo {
+	rr.roomMtx.RLock()
+	defer rr.roomMtx.RUnlock()
+
+	rooms := make([]RoomInfo, 0, len(rr.rooms))
+	for _, game := range rr.rooms {
+		rooms = append(rooms, game.CreateRoomInfo())
+	}
+	slices.SortFunc(rooms, func(a, b RoomInfo) int {
+		return strings.Compare(a.RoomCode, b.RoomCode)
+	})
+	return rooms
+}
+
+func (rr *RoomRegistry) FindRoomByPlayer(playerId string) (*Game, bool) {
+	rr.roomMtx.RLock()
+	defer rr.roomMtx.RUnlock()
+
+	for _, game := range rr.rooms {
+		if game.HasPlayer(playerId) {
+			return game, true
+		}
+	}
+	return nil, false
+}
+
+func (rr *RoomRegistry) RemoveIdleRooms(timeout time.Duration) {
+	rr.roomMtx.Lock()
+	defer rr.roomMtx.Unlock()
+
+	for code, game := range rr.rooms {
+		if !game.CloseIfIdle(timeout) {
+			continue
+		}
+		delete(rr.rooms, code)
+		delete(rr.creators, code)
+		wordStorage.RemoveRoomDecks(code)
+		if err := rr.snapshots.Delete(code); err != nil {
+			slog.Error("Failed to delete snapshot of idle room.", "roomCode", code, "err", err)
+		}
+		slog.Info("Idle room removed.", "roomCode", code)
+	}
+}
+
+// Shutdown stops all rooms, saving games in progress so they can be restored after a restart.
+func (rr *RoomRegistry) Shutdown() {
+	rr.roomMtx.RLock()
+	defer rr.roomMtx.RUnlock()
+
+	for _, game := range rr.rooms {
+		game.Shutdown()
+	}
+}
+
+func (rr *RoomRegistry) runCleanup(interval time.Duration, timeout time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		rr.RemoveIdleRooms(timeout)
+	}
+}
@@ -3,23 +3,64 @@ package main
 import "time"
 
 type Round struct {
-	Team        Team
-	GuesserId   string
-	HintGiverId string
-	Duration    int
-	Words       []*TabooWord
-	StartTime   int64
+	Team        Team     `json:"team"`
+	GuesserIds  []string `json:"guesserIds"`
+	HintGiverId string   `json:"hintGiverId"`
+	// Time left in whole seconds when the round was set up or paused
+	Duration int          `json:"duration"`
+	Words    []*TabooWord `json:"words"`
+	// Time the round timer was last started in milliseconds, 0 while the timer is stopped
+	StartTime int64 `json:"startTime"`
+	// Time left in milliseconds when the timer was last started or stopped
+	RemainingTime int64 `json:"remainingTime"`
+	// Number of words skipped this round
+	SkipCount int `json:"skipCount"`
+	// Number of consecutive guesses without a skip or buzz
+	Streak int `json:"streak"`
+	// Outcomes of words finished this round
+	Results []WordResult `json:"results"`
+	// Time the current word was shown or the round resumed in milliseconds
+	WordStartTime int64 `json:"wordStartTime"`
+	// Time spent on the current word before the round was paused in milliseconds
+	WordElapsed int64 `json:"wordElapsed"`
 }
 
 func (r *Round) SetDuration(duration int) {
 	r.Duration = duration
 }
 
+// StartTimer starts or resumes counting down the remaining time.
+func (r *Round) StartTimer() {
+	r.StartTime = time.Now().UnixMilli()
+}
+
+// StopTimer keeps the remaining time with millisecond precision while the round is paused.
+func (r *Round) StopTimer() {
+	r.RemainingTime = r.CalculateRemainingTime()
+	r.StartTime = 0
+	r.SetDuration(r.CalculateRoundPausedDuration())
+}
+
+// CalculateRemainingTime returns the time left in the round in milliseconds.
+func (r Round) CalculateRemainingTime() int64 {
+	if r.StartTime == 0 {
+		return r.RemainingTime
+	}
+	return max(r.RemainingTime-(time.Now().UnixMilli()-r.StartTime), 0)
+}
+
+func (r Round) CreateTimingProperty() TimingProperty {
+	return TimingProperty{
+		ServerTime:    time.Now().UnixMilli(),
+		RemainingTime: r.CalculateRemainingTime(),
+	}
+}
+
 func (r Round) CreateRoundSetupMessage() *RoundSetupMessage {
 	return &RoundSetupMessage{
 		TypeProperty: TypeProperty{Type: RoundSetupMsg},
 		Team:         r.Team,
-		GuesserId:    r.GuesserId,
+		GuesserIds:   r.GuesserIds,
 		HintGiverId:  r.HintGiverId,
 		Duration:     r.Duration,
 		Words:        r.Words,
@@ -30,6 +71,14 @@ func (r Round) CreateRoundStartedMessage() *RoundStartedMessage {
 	return &RoundStartedMessage{
 		TypeProperty:     TypeProperty{Type: RoundStartedMsg},
 		PlayerIdProperty: PlayerIdProperty{PlayerId: r.HintGiverId},
+		TimingProperty:   r.CreateTimingProperty(),
+	}
+}
+
+func (r Round) CreateRoundSummaryMessage(roundNumber uint) *RoundSummaryMessage {
+	return &RoundSummaryMessage{
+		TypeProperty: TypeProperty{Type: RoundSummaryMsg},
+		RoundSummary: r.CreateRoundSummary(roundNumber),
 	}
 }
 
@@ -43,6 +92,7 @@ func (r Round) CreateRoundPausedMessage() *RoundPausedMessage {
 	return &RoundPausedMessage{
 		TypeProperty:      TypeProperty{Type: RoundPausedMsg},
 		RemainingDuration: r.Duration,
+		TimingProperty:    r.CreateTimingProperty(),
 	}
 }
 
@@ -50,14 +100,18 @@ func (r Round) CreateRoundResumedMessage() *RoundResumedMessage {
 	return &RoundResumedMessage{
 		TypeProperty:     TypeProperty{Type: RoundResumedMsg},
 		PlayerIdProperty: PlayerIdProperty{PlayerId: r.HintGiverId},
+		TimingProperty:   r.CreateTimingProperty(),
 	}
 }
 
-func (r Round) CalculateRoundPausedDuration() int {
-	endTime := r.StartTime + int64(r.Duration)*1000
-	remaining := endTime - time.Now().UnixMilli()
-	if remaining < 0 {
-		return 0
+func (r Round) CreateTimerTickMessage() *TimerTickMessage {
+	return &TimerTickMessage{
+		TypeProperty:   TypeProperty{Type: TimerTickMsg},
+		TimingProperty: r.CreateTimingProperty(),
 	}
-	return int(remaining / 1000)
+}
+
+// CalculateRoundPausedDuration returns the time left in the round in seconds, rounded up.
+func (r Round) CalculateRoundPausedDuration() int {
+	return int((r.CalculateRemainingTime() + 999) / 1000)
 }
@@ -0,0 +1,114 @@
+package main
+
+const DefaultRoundDuration = 60
+const DefaultBatchSize = 10
+const DefaultMaxRounds = 4
+const DefaultMaxPlayers = 4
+const DefaultMaxTeamMembers = 2
+const DefaultTeamCount = 2
+const DefaultMaxPauses = 3
+const DefaultGraceTimeout = 60
+
+type PausePolicy string
+
+const (
+	// Wait for the hint giver or host to resume the round
+	ManualPausePolicy PausePolicy = "manual"
+	// Resume the round once every player has reconnected
+	AutoResumePolicy PausePolicy = "auto_resume"
+	// Continue without missing players once the grace timeout passes
+	ReplacePausePolicy PausePolicy = "replace"
+	// End the round once the grace timeout passes
+	ForfeitPausePolicy PausePolicy = "forfeit"
+)
+
+type GameSettings struct {
+	// Round duration in seconds
+	RoundDuration int `json:"roundDuration"`
+	// Number of words sent to players in one batch
+	BatchSize int `json:"batchSize"`
+	// Number of rounds played before the game ends
+	MaxRounds uint `json:"maxRounds"`
+	// Maximum number of players in the room
+	MaxPlayers int `json:"maxPlayers"`
+	// Maximum number of players in a single team
+	MaxTeamMembers int `json:"maxTeamMembers"`
+	// Number of teams taking turns
+	TeamCount int `json:"teamCount"`
+	// Points awarded and deducted for word actions
+	Scoring ScoringPolicy `json:"scoring"`
+	// IDs of decks words are drawn from
+	Decks []string `json:"decks"`
+	// Only the host can move players between teams
+	TeamsLocked bool `json:"teamsLocked"`
+	// Number of manual round pauses allowed per game, 0 disables pausing
+	MaxPauses int `json:"maxPauses"`
+	// What happens to a round paused because a player disconnected
+	PausePolicy PausePolicy `json:"pausePolicy"`
+	// Seconds to wait for disconnected players before the pause policy applies
+	GraceTimeout int `json:"graceTimeout"`
+}
+
+func DefaultGameSettings() GameSettings {
+	return GameSettings{
+		RoundDuration:  DefaultRoundDuration,
+		BatchSize:      DefaultBatchSize,
+		MaxRounds:      DefaultMaxRounds,
+		MaxPlayers:     DefaultMaxPlayers,
+		MaxTeamMembers: DefaultMaxTeamMembers,
+		TeamCount:      DefaultTeamCount,
+		Scoring:        DefaultScoringPolicy(),
+		Decks:          wordStorage.GetDeckIds(),
+		TeamsLocked:    false,
+		MaxPauses:      DefaultMaxPauses,
+		PausePolicy:    ManualPausePolicy,
+		GraceTimeout:   DefaultGraceTimeout,
+	}
+}
+
+type ScoringPolicy struct {
+	// Points awarded for a guessed word
+	GuessPoints int `json:"guessPoints"`
+	// Points deducted for a skipped word once free skips are used up
+	SkipPenalty int `json:"skipPenalty"`
+	// Number of skips per round without penalty
+	FreeSkips int `json:"freeSkips"`
+	// Points deducted from the playing team when a taboo word is said
+	BuzzPenalty int `json:"buzzPenalty"`
+	// Number of consecutive guesses completing a streak, 0 disables streaks
+	StreakLength int `json:"streakLength"`
+	// Extra points awarded for each completed streak
+	StreakBonus int `json:"streakBonus"`
+}
+
+func DefaultScoringPolicy() ScoringPolicy {
+	return ScoringPolicy{
+		GuessPoints:  1,
+		SkipPenalty:  0,
+		FreeSkips:    0,
+		BuzzPenalty:  1,
+		StreakLength: 0,
+		StreakBonus:  0,
+	}
+}
+
+// GuessScore returns points for a guess given the number of consecutive guesses including this one.
+func (sp ScoringPolicy) GuessScore(streak int) int {
+	points := sp.GuessPoints
+	if sp.StreakLength > 0 && streak%sp.StreakLength == 0 {
+		points += sp.StreakBonus
+	}
+	return points
+}
+
+// SkipScore returns points for a skip given the number of skips in the round including this one.
+func (sp ScoringPolicy) SkipScore(skips int) int {
+	if skips <= sp.FreeSkips {
+		return 0
+	}
+	return -sp.SkipPenalty
+}
+
+func (sp ScoringPolicy) BuzzScore() int {
+	return -sp.BuzzPenalty
+}
@@ -0,0 +1,50 @@
+package main
+
+import "testing"
+
+func TestGuessScore(t *testing.T) {
+	streaks := ScoringPolicy{GuessPoints: 2, StreakLength: 3, StreakBonus: 5}
+	tests := []struct {
+		name   string
+		policy ScoringPolicy
+		streak int
+		want   int
+	}{
+		{"streaks disabled", ScoringPolicy{GuessPoints: 2, StreakBonus: 5}, 3, 2},
+		{"first guess", streaks, 1, 2},
+		{"before streak completes", streaks, 2, 2},
+		{"streak completes", streaks, 3, 7},
+		{"after completed streak", streaks, 4, 2},
+		{"second streak completes", streaks, 6, 7},
+		{"every guess completes a streak", ScoringPolicy{GuessPoints: 1, StreakLength: 1, StreakBonus: 1}, 5, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.GuessScore(tt.streak); got != tt.want {
+				t.Errorf("GuessScore(%d) = %d, want %d", tt.streak, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSkipScore(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy ScoringPolicy
+		skips  int
+		want   int
+	}{
+		{"no free skips", ScoringPolicy{SkipPenalty: 2}, 1, -2},
+		{"within free skips", ScoringPolicy{SkipPenalty: 2, FreeSkips: 2}, 1, 0},
+		{"last free skip", ScoringPolicy{SkipPenalty: 2, FreeSkips: 2}, 2, 0},
+		{"first penalized skip", ScoringPolicy{SkipPenalty: 2, FreeSkips: 2}, 3, -2},
+		{"no penalty", ScoringPolicy{FreeSkips: 1}, 4, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.SkipScore(tt.skips); got != tt.want {
+				t.Errorf("SkipScore(%d) = %d, want %d", tt.skips, got, tt.want)
+			}
+		})
+	}
+}
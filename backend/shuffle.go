@@ -0,0 +1,167 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"slices"
+)
+
+type ShuffleMode string
+
+const (
+	// Deal players to teams in random order
+	RandomShuffle ShuffleMode = "random"
+	// Spread players with the best profile statistics evenly across teams
+	BalancedShuffle ShuffleMode = "balanced"
+)
+
+type TeamAssignment struct {
+	PlayerId string `json:"playerId"`
+	Team     Team   `json:"team"`
+}
+
+// shuffleTeams lets the host reassign all connected players to teams at once, players who do not fit
+// into the teams stay unassigned.
+func (g *Game) shuffleTeams(playerId string, mode ShuffleMode) error {
+	g.playerMtx.Lock()
+	defer g.playerMtx.Unlock()
+
+	player, exists := g.players[playerId]
+	if !exists {
+		return fmt.Errorf("player with ID %s not found", playerId)
+	}
+
+	if err := g.checkHostUnlocked(player, ShuffleTeamsMsg); err != nil {
+		return err
+	}
+
+	if g.gameState != InLobby {
+		SendErrorMessage(
+			player,
+			*CreateErrorMessage(
+				ShuffleTeamsMsg,
+				ErrGameNotInLobby,
+			),
+		)
+		return fmt.Errorf("game not in lobby state, cannot shuffle teams")
+	}
+
+	ids := make([]string, 0, len(g.players))
+	for id, p := range g.players {
+		if p.connected {
+			ids = append(ids, id)
+		}
+	}
+	rand.Shuffle(len(ids), func(i, j int) {
+		ids[i], ids[j] = ids[j], ids[i]
+	})
+
+	var skills map[string]float64
+	if mode == BalancedShuffle {
+		skills = g.playerSkillsUnlocked(ids)
+		if skills == nil {
+			// nobody has played a game with a profile yet
+			mode = RandomShuffle
+		}
+	}
+	teams := g.distributePlayersUnlocked(ids, skills)
+
+	clear(g.teamPlayers)
+	for _, p := range g.players {
+		p.SetTeam(Unassigned)
+		p.SetReady(false)
+	}
+	for team, members := range teams {
+		for _, id := range members {
+			g.players[id].SetTeam(team)
+		}
+		g.teamPlayers[team] = members
+	}
+	slog.Info("Teams shuffled.", "roomCode", g.code, "mode", mode, "teamplayers", g.teamPlayers)
+
+	assignments := make([]TeamAssignment, 0, len(g.players))
+	for id, p := range g.players {
+		assignments = append(assignments, TeamAssignment{
+			PlayerId: id,
+			Team:     p.team,
+		})
+	}
+	players := g.GetPlayersCopyUnlocked()
+	shuffledMsg := &TeamsShuffledMessage{
+		TypeProperty: TypeProperty{
+			Type: TeamsShuffledMsg,
+		},
+		Mode:    mode,
+		Players: assignments,
+	}
+	return g.BroadcastMessage(players, shuffledMsg, nil)
+}
+
+// distributePlayersUnlocked deals players to the team with the fewest members, and on ties the lowest total skill.
+// Without skills the players are dealt in the given order.
+func (g *Game) distributePlayersUnlocked(ids []string, skills map[string]float64) map[Team][]string {
+	if skills != nil {
+		slices.SortStableFunc(ids, func(a, b string) int {
+			return cmp.Compare(skills[b], skills[a])
+		})
+	}
+
+	teamInfos := GetTeamInfos(g.settings.TeamCount)
+	teams := make(map[Team][]string, len(teamInfos))
+	totals := make(map[Team]float64, len(teamInfos))
+	for _, id := range ids {
+		team := Unassigned
+		for _, info := range teamInfos {
+			size := len(teams[info.Id])
+			if size >= g.settings.MaxTeamMembers {
+				continue
+			}
+			if team == Unassigned || size < len(teams[team]) || size == len(teams[team]) && totals[info.Id] < totals[team] {
+				team = info.Id
+			}
+		}
+		if team == Unassigned {
+			// every team is full
+			break
+		}
+		teams[team] = append(teams[team], id)
+		totals[team] += skills[id]
+	}
+	return teams
+}
+
+// playerSkillsUnlocked rates players by their profile statistics, players without any get the average rating.
+// Returns nil if no player has statistics.
+func (g *Game) playerSkillsUnlocked(ids []string) map[string]float64 {
+	if g.profiles == nil {
+		return nil
+	}
+
+	skills := make(map[string]float64, len(ids))
+	total := 0.0
+	for _, id := range ids {
+		player := g.players[id]
+		if player.profileId == "" {
+			continue
+		}
+		stats, exists := g.profiles.GetStats(player.profileId)
+		if !exists || stats.GamesPlayed == 0 {
+			continue
+		}
+		skills[id] = stats.Skill()
+		total += skills[id]
+	}
+	if len(skills) == 0 {
+		return nil
+	}
+
+	average := total / float64(len(skills))
+	for _, id := range ids {
+		if _, rated := skills[id]; !rated {
+			skills[id] = average
+		}
+	}
+	return skills
+}
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+type PlayerSnapshot struct {
+	Id           string `json:"id"`
+	SessionToken string `json:"sessionToken"`
+	Name         string `json:"name"`
+	IsReady      bool   `json:"isReady"`
+	Team         Team   `json:"team"`
+	ProfileId    string `json:"profileId,omitempty"`
+}
+
+type GameSnapshot struct {
+	Code             string            `json:"code"`
+	HistoryId        string            `json:"historyId,omitempty"`
+	Settings         GameSettings      `json:"settings"`
+	HostId           string            `json:"hostId"`
+	GameState        GameState         `json:"gameState"`
+	Players          []PlayerSnapshot  `json:"players"`
+	TeamPlayers      map[Team][]string `json:"teamPlayers"`
+	TeamScores       map[Team]int      `json:"teamScores"`
+	HintsGiven       map[string]uint   `json:"hintsGiven"`
+	WordIds          []uint            `json:"wordIds"`
+	BatchedWordCount uint              `json:"batchedWordCount"`
+	WordQueue        []uint            `json:"wordQueue"`
+	CurrentWordIdx   uint              `json:"currentWordIdx"`
+	RoundNumber      uint              `json:"roundNumber"`
+	CurrentRound     *Round            `json:"currentRound"`
+	RoundSummaries   []RoundSummary    `json:"roundSummaries"`
+	PauseCount       int               `json:"pauseCount"`
+	CustomDecks      []*Deck           `json:"customDecks"`
+	SavedAt          time.Time         `json:"savedAt"`
+}
+
+type SnapshotStorage struct {
+	// Directory with one snapshot file per room
+	dir string
+	// File write mutex
+	fileMtx sync.Mutex
+}
+
+func CreateSnapshotStorage(dir string) (*SnapshotStorage, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create snapshot dir %s: %w", dir, err)
+	}
+	return &SnapshotStorage{
+		dir:     dir,
+		fileMtx: sync.Mutex{},
+	}, nil
+}
+
+func (ss *SnapshotStorage) path(code string) string {
+	return filepath.Join(ss.dir, code+".json")
+}
+
+func (ss *SnapshotStorage) Save(snapshot GameSnapshot) error {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot of room %s: %w", snapshot.Code, err)
+	}
+
+	ss.fileMtx.Lock()
+	defer ss.fileMtx.Unlock()
+
+	// write to a temporary file first so a crash never leaves a truncated snapshot
+	tmp := ss.path(snapshot.Code) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write snapshot of room %s: %w", snapshot.Code, err)
+	}
+	if err := os.Rename(tmp, ss.path(snapshot.Code)); err != nil {
+		return fmt.Errorf("failed to replace snapshot of room %s: %w", snapshot.Code, err)
+	}
+	return nil
+}
+
+func (ss *SnapshotStorage) Delete(code string) error {
+	ss.fileMtx.Lock()
+	defer ss.fileMtx.Unlock()
+
+	err := os.Remove(ss.path(code))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete snapshot of room %s: %w", code, err)
+	}
+	return nil
+}
+
+func (ss *SnapshotStorage) LoadAll() ([]GameSnapshot, error) {
+	files, err := os.ReadDir(ss.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot dir %s: %w", ss.dir, err)
+	}
+
+	snapshots := make([]GameSnapshot, 0, len(files))
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".json") {
+			continue
+		}
+
+		path := filepath.Join(ss.dir, file.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			slog.Warn("Failed to read snapshot file.", "file", path, "err", err)
+			continue
+		}
+
+		var snapshot GameSnapshot
+		if err := json.Unmarshal(data, &snapshot); err != nil {
+			slog.Warn("Failed to unmarshal snapshot file.", "file", path, "err", err)
+			continue
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, nil
+}
+
+func (g *Game) CreateSnapshotUnlocked() GameSnapshot {
+	players := make([]PlayerSnapshot, 0, len(g.players))
+	for _, p := range g.players {
+		if g.queueIndexUnlocked(p.id) >= 0 {
+			// queued players leave on disconnect, so they are not restored either
+			continue
+		}
+		players = append(players, PlayerSnapshot{
+			Id:           p.id,
+			SessionToken: p.sessionToken,
+			Name:         p.name,
+			IsReady:      p.isReady,
+			Team:         p.team,
+			ProfileId:    p.profileId,
+		})
+	}
+
+	var round *Round
+	if g.currentRound != nil {
+		roundCopy := *g.currentRound
+		if g.gameState == InRound {
+			// store time left so the round can be resumed after restore
+			roundCopy.StopTimer()
+		}
+		round = &roundCopy
+	}
+
+	return GameSnapshot{
+		Code:             g.code,
+		HistoryId:        g.history.Id(),
+		Settings:         g.settings,
+		HostId:           g.hostId,
+		GameState:        g.gameState,
+		Players:          players,
+		TeamPlayers:      g.teamPlayers,
+		TeamScores:       g.teamScores,
+		HintsGiven:       g.hintsGiven,
+		WordIds:          g.wordIds,
+		BatchedWordCount: g.batchedWordCount,
+		WordQueue:        g.wordQueue,
+		CurrentWordIdx:   g.currentWordIdx,
+		RoundNumber:      g.roundNumber,
+		CurrentRound:     round,
+		RoundSummaries:   g.roundSummaries,
+		PauseCount:       g.pauseCount,
+		CustomDecks:      wordStorage.GetRoomDecks(g.code),
+		SavedAt:          time.Now(),
+	}
+}
+
+// RestoreGame recreates a game from a snapshot, with every player disconnected and any active round paused.
+func RestoreGame(snapshot GameSnapshot) *Game {
+	g := CreateGame(snapshot.Code)
+	for _, deck := range snapshot.CustomDecks {
+		wordStorage.RestoreRoomDeck(deck)
+	}
+	g.settings = snapshot.Settings
+	g.hostId = snapshot.HostId
+	g.hadPlayers = len(snapshot.Players) > 0
+	g.gameState = snapshot.GameState
+	for _, p := range snapshot.Players {
+		g.players[p.Id] = &Player{
+			id:           p.Id,
+			conn:         nil,
+			sessionToken: p.SessionToken,
+			name:         p.Name,
+			isReady:      p.IsReady,
+			team:         p.Team,
+			connected:    false,
+			profileId:    p.ProfileId,
+		}
+	}
+	for team, members := range snapshot.TeamPlayers {
+		g.teamPlayers[team] = members
+	}
+	for team, score := range snapshot.TeamScores {
+		g.teamScores[team] = score
+	}
+	for id, count := range snapshot.HintsGiven {
+		g.hintsGiven[id] = count
+	}
+	g.wordIds = snapshot.WordIds
+	g.batchedWordCount = snapshot.BatchedWordCount
+	g.wordQueue = snapshot.WordQueue
+	g.currentWordIdx = snapshot.CurrentWordIdx
+	g.roundNumber = snapshot.RoundNumber
+	g.currentRound = snapshot.CurrentRound
+	if snapshot.RoundSummaries != nil {
+		g.roundSummaries = snapshot.RoundSummaries
+	}
+	g.pauseCount = snapshot.PauseCount
+	if g.gameState == InRound {
+		g.gameState = Paused
+	}
+	// players return after a restart the same way as after a disconnect
+	g.autoPaused = g.gameState == Paused
+	return g
+}
+
+// persist stores the game snapshot, or removes it once the game is back in the lobby.
+func (g *Game) persist() {
+	if g.snapshots == nil {
+		return
+	}
+
+	// the player mutex is only read locked, hold the persist mutex until the snapshot is written
+	g.persistMtx.Lock()
+	defer g.persistMtx.Unlock()
+
+	g.playerMtx.RLock()
+	if g.gameState == InLobby || len(g.players) == 0 {
+		g.playerMtx.RUnlock()
+		if err := g.snapshots.Delete(g.code); err != nil {
+			slog.Error("Failed to delete game snapshot.", "roomCode", g.code, "err", err)
+		}
+		return
+	}
+	snapshot := g.CreateSnapshotUnlocked()
+	err := g.snapshots.Save(snapshot)
+	g.playerMtx.RUnlock()
+
+	if err != nil {
+		slog.Error("Failed to save game snapshot.", "roomCode", g.code, "err", err)
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+)
+
+const MaxSpectators = 20
+
+type SpectatorInfo struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func (g *Game) AddSpectator(conn *Connection, name string) (string, error) {
+	g.playerMtx.RLock()
+	closed := g.closed
+	g.playerMtx.RUnlock()
+	if closed {
+		SendDirectErrorMessage(
+			conn,
+			*CreateErrorMessage(
+				SpectateMsg,
+				ErrRoomNotFound,
+			),
+		)
+		return "", fmt.Errorf("spectator from %s cannot connect, room %s is closed", conn.RemoteAddr(), g.code)
+	}
+
+	g.spectatorMtx.Lock()
+	if len(g.spectators) >= MaxSpectators {
+		g.spectatorMtx.Unlock()
+		SendDirectErrorMessage(
+			conn,
+			*CreateErrorMessage(
+				SpectateMsg,
+				ErrGameFull,
+			),
+		)
+		return "", fmt.Errorf("spectator from %s cannot connect, too many spectators", conn.RemoteAddr())
+	}
+	spectator := &Player{
+		id:           generateUUID(),
+		conn:         conn,
+		sessionToken: generateUUID(),
+		name:         name,
+		isReady:      false,
+		team:         Unassigned,
+		connected:    true,
+	}
+	g.spectators[spectator.id] = spectator
+	g.spectatorMtx.Unlock()
+
+	connectMsg := ConnectAckMessage{
+		TypeProperty: TypeProperty{
+			Type: ConnectAckMsg,
+		},
+		PlayerIdProperty: PlayerIdProperty{
+			PlayerId: spectator.id,
+		},
+		SessionToken: spectator.sessionToken,
+		Name:         name,
+		RoomCode:     g.code,
+		Spectator:    true,
+	}
+	if err := SendUnicastMessage(spectator, connectMsg); err != nil {
+		slog.Warn("Failed to send connect ack message.", "playerId", spectator.id, "err", err)
+	}
+
+	listMsg := g.CreatePlayerListMessage()
+	if err := SendUnicastMessage(spectator, listMsg); err != nil {
+		slog.Warn("Failed to send player list message.", "playerId", spectator.id, "err", err)
+	}
+
+	g.playerMtx.RLock()
+	if g.currentRound != nil && g.gameState != InLobby && g.gameState != Ended {
+		// let spectators joining mid-game know who is playing, without the secret words
+		setupMsg := g.currentRound.CreateRoundSetupMessage().Redact()
+		if err := SendUnicastMessage(spectator, setupMsg); err != nil {
+			slog.Warn("Failed to send round setup message.", "playerId", spectator.id, "err", err)
+		}
+	}
+	players := g.GetPlayersCopyUnlocked()
+	g.playerMtx.RUnlock()
+
+	joinedMsg := &SpectatorJoinedMessage{
+		TypeProperty: TypeProperty{
+			Type: SpectatorJoinedMsg,
+		},
+		PlayerIdProperty: PlayerIdProperty{
+			PlayerId: spectator.id,
+		},
+		Name: name,
+	}
+	g.BroadcastMessage(players, joinedMsg, &spectator.id)
+
+	return spectator.id, nil
+}
+
+func (g *Game) RemoveSpectator(spectatorId string) {
+	g.spectatorMtx.Lock()
+	if _, exists := g.spectators[spectatorId]; !exists {
+		g.spectatorMtx.Unlock()
+		slog.Error("Spectator not found.", "playerId", spectatorId)
+		return
+	}
+	delete(g.spectators, spectatorId)
+	g.spectatorMtx.Unlock()
+
+	leftMsg := &SpectatorLeftMessage{
+		TypeProperty: TypeProperty{
+			Type: SpectatorLeftMsg,
+		},
+		PlayerIdProperty: PlayerIdProperty{
+			PlayerId: spectatorId,
+		},
+	}
+	g.BroadcastMessage(g.GetPlayersCopy(), leftMsg, nil)
+}
+
+func (g *Game) GetSpectatorsCopy() map[string]*Player {
+	g.spectatorMtx.RLock()
+	defer g.spectatorMtx.RUnlock()
+	return maps.Clone(g.spectators)
+}
+
+func (g *Game) CreateSpectatorList() []SpectatorInfo {
+	g.spectatorMtx.RLock()
+	defer g.spectatorMtx.RUnlock()
+
+	spectators := make([]SpectatorInfo, 0, len(g.spectators))
+	for _, s := range g.spectators {
+		spectators = append(spectators, SpectatorInfo{
+			Id:   s.id,
+			Name: s.name,
+		})
+	}
+	return spectators
+}
+
+// broadcastToSpectators forwards a broadcast to spectators, who only see redacted words.
+func (g *Game) broadcastToSpectators(msg MessageBase, excluded *string) {
+	spectators := g.GetSpectatorsCopy()
+	if len(spectators) == 0 {
+		return
+	}
+
+	if redactable, ok := msg.(RedactableMessage); ok {
+		msg = redactable.Redact()
+	}
+	BroadcastMessage(spectators, msg, excluded)
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"cmp"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+)
+
+type WordOutcome string
+
+const (
+	WordOutcomeGuessed WordOutcome = "guessed"
+	WordOutcomeSkipped WordOutcome = "skipped"
+	WordOutcomeBuzzed  WordOutcome = "buzzed"
+)
+
+type WordResult struct {
+	WordId  uint        `json:"wordId"`
+	Word    string      `json:"word"`
+	Outcome WordOutcome `json:"outcome"`
+	// ID of the player who marked the outcome
+	PlayerId string `json:"playerId"`
+	// Points gained or lost by the playing team
+	Points int `json:"points"`
+	// Time spent on the word in milliseconds
+	Duration int64 `json:"duration"`
+}
+
+type RoundSummary struct {
+	RoundNumber uint         `json:"roundNumber"`
+	Team        Team         `json:"team"`
+	HintGiverId string       `json:"hintGiverId"`
+	GuesserIds  []string     `json:"guesserIds"`
+	Guessed     int          `json:"guessed"`
+	Skipped     int          `json:"skipped"`
+	Buzzed      int          `json:"buzzed"`
+	Points      int          `json:"points"`
+	Words       []WordResult `json:"words"`
+}
+
+type PlayerStats struct {
+	PlayerId string `json:"playerId"`
+	Name     string `json:"name"`
+	Team     Team   `json:"team"`
+	// Words guessed while the player was the hint giver
+	WordsDescribed int `json:"wordsDescribed"`
+	// Words guessed while the player was a guesser
+	WordsGuessed int `json:"wordsGuessed"`
+	// Taboo words called out by the player
+	Buzzes int `json:"buzzes"`
+	// Average time in milliseconds to get a word guessed as the hint giver
+	AverageWordTime int64 `json:"averageWordTime"`
+	// Total time in milliseconds spent on described words that were guessed
+	wordTime int64
+}
+
+// RecordWordResult stores the outcome of the current word and restarts the word timer.
+func (r *Round) RecordWordResult(word *TabooWord, outcome WordOutcome, playerId string, points int) {
+	now := time.Now().UnixMilli()
+	result := WordResult{
+		Outcome:  outcome,
+		PlayerId: playerId,
+		Points:   points,
+		Duration: r.WordElapsed + now - r.WordStartTime,
+	}
+	if word != nil {
+		result.WordId = word.ID
+		result.Word = word.Word
+	}
+	r.Results = append(r.Results, result)
+	r.WordElapsed = 0
+	r.WordStartTime = now
+}
+
+// StartWordTimer starts or resumes measuring time spent on the current word.
+func (r *Round) StartWordTimer() {
+	r.WordStartTime = time.Now().UnixMilli()
+}
+
+// PauseWordTimer keeps time spent on the current word so far while the round is paused.
+func (r *Round) PauseWordTimer() {
+	r.WordElapsed += time.Now().UnixMilli() - r.WordStartTime
+}
+
+func (r Round) CreateRoundSummary(roundNumber uint) RoundSummary {
+	summary := RoundSummary{
+		RoundNumber: roundNumber,
+		Team:        r.Team,
+		HintGiverId: r.HintGiverId,
+		GuesserIds:  r.GuesserIds,
+		Words:       append([]WordResult{}, r.Results...),
+	}
+	for _, result := range r.Results {
+		switch result.Outcome {
+		case WordOutcomeGuessed:
+			summary.Guessed++
+		case WordOutcomeSkipped:
+			summary.Skipped++
+		case WordOutcomeBuzzed:
+			summary.Buzzed++
+		}
+		summary.Points += result.Points
+	}
+	return summary
+}
+
+// currentWordUnlocked returns the word currently being described, nil if the queue is exhausted.
+func (g *Game) currentWordUnlocked() *TabooWord {
+	if int(g.currentWordIdx) >= len(g.wordQueue) {
+		return nil
+	}
+	words := wordStorage.GetWordsByIds(g.wordQueue[g.currentWordIdx : g.currentWordIdx+1])
+	if len(words) == 0 {
+		return nil
+	}
+	return words[0]
+}
+
+// CreatePlayerStatsUnlocked aggregates per player statistics from all finished rounds.
+func (g *Game) CreatePlayerStatsUnlocked() []PlayerStats {
+	stats := make(map[string]*PlayerStats, len(g.players))
+	describeTime := make(map[string]int64, len(g.players))
+	get := func(playerId string) *PlayerStats {
+		s, exists := stats[playerId]
+		if !exists {
+			s = &PlayerStats{PlayerId: playerId, Team: Unassigned}
+			if player, ok := g.players[playerId]; ok {
+				s.Name = player.name
+				s.Team = player.team
+			}
+			stats[playerId] = s
+		}
+		return s
+	}
+
+	for _, player := range g.players {
+		get(player.id)
+	}
+	for _, round := range g.roundSummaries {
+		hintGiver := get(round.HintGiverId)
+		for _, result := range round.Words {
+			switch result.Outcome {
+			case WordOutcomeGuessed:
+				hintGiver.WordsDescribed++
+				describeTime[round.HintGiverId] += result.Duration
+				for _, guesserId := range round.GuesserIds {
+					get(guesserId).WordsGuessed++
+				}
+			case WordOutcomeBuzzed:
+				get(result.PlayerId).Buzzes++
+			}
+		}
+	}
+
+	list := make([]PlayerStats, 0, len(stats))
+	for id, s := range stats {
+		s.wordTime = describeTime[id]
+		if s.WordsDescribed > 0 {
+			s.AverageWordTime = s.wordTime / int64(s.WordsDescribed)
+		}
+		list = append(list, *s)
+	}
+	slices.SortFunc(list, func(a, b PlayerStats) int {
+		return cmp.Or(cmp.Compare(a.Team, b.Team), strings.Compare(a.Name, b.Name))
+	})
+	return list
+}
+
+// WinningTeamsUnlocked returns the teams sharing the highest score.
+func (g *Game) WinningTeamsUnlocked() []Team {
+	winners := []Team{}
+	best := 0
+	for _, team := range GetTeamInfos(g.settings.TeamCount) {
+		score := g.teamScores[team.Id]
+		if len(winners) == 0 || score > best {
+			winners = []Team{team.Id}
+			best = score
+		} else if score == best {
+			winners = append(winners, team.Id)
+		}
+	}
+	return winners
+}
+
+func (g *Game) CreateGameSummaryMessageUnlocked() *GameSummaryMessage {
+	return &GameSummaryMessage{
+		TypeProperty: TypeProperty{
+			Type: GameSummaryMsg,
+		},
+		GameId:  g.history.Id(),
+		Scores:  g.CreateScoreMap(),
+		Winners: g.WinningTeamsUnlocked(),
+		Rounds:  g.roundSummaries,
+		Players: g.CreatePlayerStatsUnlocked(),
+	}
+}
+
+// recordProfileStatsUnlocked adds results of the finished game to profiles of registered players on a team.
+func (g *Game) recordProfileStatsUnlocked(summary *GameSummaryMessage) {
+	if g.profiles == nil {
+		return
+	}
+
+	results := make(map[string]ProfileStats)
+	for _, stats := range summary.Players {
+		player, exists := g.players[stats.PlayerId]
+		if !exists || player.profileId == "" {
+			continue
+		}
+		// unassigned and queued players did not take part in the game
+		if !player.team.IsPlaying(g.settings.TeamCount) {
+			continue
+		}
+		result := ProfileStats{
+			GamesPlayed:    1,
+			WordsDescribed: stats.WordsDescribed,
+			WordsGuessed:   stats.WordsGuessed,
+			WordTime:       stats.wordTime,
+		}
+		if slices.Contains(summary.Winners, player.team) {
+			result.Wins = 1
+		}
+		results[player.profileId] = result
+	}
+	if len(results) == 0 {
+		return
+	}
+	if err := g.profiles.RecordGame(results); err != nil {
+		slog.Error("Failed to record profile statistics.", "roomCode", g.code, "err", err)
+	}
+}
@@ -2,26 +2,39 @@ package main
 
 type Team int
 
-const MaxTeamMembers = 2
+const MinTeams = 2
+const MaxTeams = 6
+const MinTeamMembers = 2
 
 const (
 	Unassigned Team = -1
 	Red        Team = 0
 	Blue       Team = 1
+	Green      Team = 2
+	Yellow     Team = 3
+	Purple     Team = 4
+	Orange     Team = 5
 )
 
-type TeamState struct {
-	MemberCount int
-	Score       int
+type TeamInfo struct {
+	Id    Team   `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
 }
 
-func (t Team) GetOppositeTeam() Team {
-	switch t {
-	case Red:
-		return Blue
-	case Blue:
-		return Red
-	default:
-		return Unassigned
-	}
+var teamInfos = [MaxTeams]TeamInfo{
+	{Id: Red, Name: "Red", Color: "#e53935"},
+	{Id: Blue, Name: "Blue", Color: "#1e88e5"},
+	{Id: Green, Name: "Green", Color: "#43a047"},
+	{Id: Yellow, Name: "Yellow", Color: "#fdd835"},
+	{Id: Purple, Name: "Purple", Color: "#8e24aa"},
+	{Id: Orange, Name: "Orange", Color: "#fb8c00"},
+}
+
+func GetTeamInfos(teamCount int) []TeamInfo {
+	return append([]TeamInfo(nil), teamInfos[:teamCount]...)
+}
+
+func (t Team) IsPlaying(teamCount int) bool {
+	return t >= 0 && int(t) < teamCount
 }
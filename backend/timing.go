@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Time between timer ticks broadcast while a round is running
+const TimerTickInterval = time.Second
+
+// startRoundTimerUnlocked counts down the time left in the current round, broadcasting a timer tick
+// every interval and ending the round once the time runs out.
+func (g *Game) startRoundTimerUnlocked() {
+	g.currentRound.StartTimer()
+	remaining := time.Duration(g.currentRound.RemainingTime) * time.Millisecond
+	g.roundCtx, g.roundCancel = context.WithCancel(context.Background())
+	go func(ctx context.Context) {
+		endTimer := time.NewTimer(remaining)
+		ticker := time.NewTicker(TimerTickInterval)
+		defer endTimer.Stop()
+		defer ticker.Stop()
+		for {
+			select {
+			case <-endTimer.C:
+				g.endRound(ctx)
+				return
+			case <-ticker.C:
+				g.broadcastTimerTick(ctx)
+			case <-ctx.Done():
+				slog.Info("Round timer cancelled.", "roomCode", g.code)
+				return
+			}
+		}
+	}(g.roundCtx)
+}
+
+func (g *Game) broadcastTimerTick(ctx context.Context) {
+	g.playerMtx.RLock()
+	defer g.playerMtx.RUnlock()
+
+	// the round may have been paused or ended while waiting for the lock
+	if ctx.Err() != nil || g.gameState != InRound {
+		return
+	}
+	// ticks are not recorded in the history, replays derive them from round started and resumed events
+	tickMsg := g.currentRound.CreateTimerTickMessage()
+	g.broadcastToSpectators(tickMsg, nil)
+	if err := BroadcastMessage(g.GetPlayersCopyUnlocked(), tickMsg, nil); err != nil {
+		slog.Warn("Failed to broadcast timer tick message.", "roomCode", g.code, "err", err)
+	}
+}
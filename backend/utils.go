@@ -1,7 +1,22 @@
 package main
 
-import "github.com/google/uuid"
+import (
+	"math/rand"
+
+	"github.com/google/uuid"
+)
+
+// room code alphabet without easily confused characters (0/O, 1/I/L)
+const roomCodeAlphabet = "ABCDEFGHJKMNPQRSTUVWXYZ23456789"
 
 func generateUUID() string {
 	return uuid.NewString()
 }
+
+func generateRoomCode(length int) string {
+	code := make([]byte, length)
+	for i := range code {
+		code[i] = roomCodeAlphabet[rand.Intn(len(roomCodeAlphabet))]
+	}
+	return string(code)
+}
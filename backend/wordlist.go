@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type WordListFormat string
+
+const (
+	JSONWordList WordListFormat = "json"
+	CSVWordList  WordListFormat = "csv"
+)
+
+const TabooWordSchema MessageType = "taboo_word"
+const MaxUploadedWords = 500
+
+// ParseWordList reads words from a JSON array of word objects or from CSV rows of a word followed by its taboos.
+func ParseWordList(format WordListFormat, data string) ([]*TabooWord, error) {
+	switch format {
+	case JSONWordList:
+		var words []*TabooWord
+		if err := json.Unmarshal([]byte(data), &words); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal word list: %w", err)
+		}
+		for i, word := range words {
+			if word == nil {
+				return nil, fmt.Errorf("word %d of the word list is null", i+1)
+			}
+		}
+		return words, nil
+	case CSVWordList:
+		words, _, err := parseCSVWords(data)
+		return words, err
+	default:
+		return nil, fmt.Errorf("unsupported word list format: %s", format)
+	}
+}
+
+// parseCSVWords reads CSV rows of a word followed by its taboos, returning the line number of each word.
+func parseCSVWords(data string) ([]*TabooWord, []int, error) {
+	reader := csv.NewReader(strings.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	words := []*TabooWord{}
+	lines := []int{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read word list CSV: %w", err)
+		}
+		// skip optional header row
+		line, _ := reader.FieldPos(0)
+		if line == 1 && strings.EqualFold(record[0], "word") {
+			continue
+		}
+		taboos := make([]string, 0, len(record)-1)
+		for _, taboo := range record[1:] {
+			taboos = append(taboos, strings.TrimSpace(taboo))
+		}
+		words = append(words, &TabooWord{
+			Word:   strings.TrimSpace(record[0]),
+			Taboos: taboos,
+		})
+		lines = append(lines, line)
+	}
+	return words, lines, nil
+}
+
+// ValidateWord checks the word against the same schema used for words sent to players.
+func ValidateWord(ss *SchemaStorage, word *TabooWord) error {
+	data, err := json.Marshal(word)
+	if err != nil {
+		return fmt.Errorf("failed to marshal word %s: %w", word.Word, err)
+	}
+	if err := ss.validate(TabooWordSchema, data); err != nil {
+		return fmt.Errorf("word %q is not valid: %w", word.Word, err)
+	}
+	return nil
+}
@@ -3,8 +3,12 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"math/rand"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"sync"
 )
 
@@ -14,8 +18,46 @@ type TabooWord struct {
 	Taboos []string `json:"taboo"`
 }
 
+// RedactedWord identifies a word without revealing it to players who have to guess it.
+type RedactedWord struct {
+	ID uint `json:"id"`
+}
+
+func RedactWords(words []*TabooWord) []RedactedWord {
+	redacted := make([]RedactedWord, 0, len(words))
+	for _, word := range words {
+		redacted = append(redacted, RedactedWord{ID: word.ID})
+	}
+	return redacted
+}
+
+// ID of the first word in decks uploaded by players
+const CustomWordIdStart = 1000000
+
+type Deck struct {
+	Id       string       `json:"id,omitempty"`
+	Owner    string       `json:"owner,omitempty"`
+	Title    string       `json:"title"`
+	Language string       `json:"language"`
+	Category string       `json:"category"`
+	Words    []*TabooWord `json:"words"`
+}
+
+type DeckInfo struct {
+	Id        string `json:"id"`
+	Title     string `json:"title"`
+	Language  string `json:"language"`
+	Category  string `json:"category"`
+	WordCount int    `json:"wordCount"`
+}
+
 type WordStorage struct {
-	words map[uint]*TabooWord
+	// Word storage mutex
+	wordMtx sync.RWMutex
+	words   map[uint]*TabooWord
+	decks   map[string]*Deck
+	// Next ID assigned to an uploaded word
+	nextCustomId uint
 }
 
 var (
@@ -27,9 +69,12 @@ func GetWordStorage() (*WordStorage, error) {
 	var err error
 	wOnce.Do(func() {
 		wordStorage = &WordStorage{
-			words: make(map[uint]*TabooWord),
+			wordMtx:      sync.RWMutex{},
+			words:        make(map[uint]*TabooWord),
+			decks:        make(map[string]*Deck),
+			nextCustomId: CustomWordIdStart,
 		}
-		err = wordStorage.loadWords("words.json")
+		err = wordStorage.loadDecks("decks/")
 	})
 	if err != nil {
 		return nil, err
@@ -37,10 +82,19 @@ func GetWordStorage() (*WordStorage, error) {
 	return wordStorage, nil
 }
 
-func (ws *WordStorage) GetShuffledIds() []uint {
+func (ws *WordStorage) GetShuffledIds(deckIds []string) []uint {
+	ws.wordMtx.RLock()
+	defer ws.wordMtx.RUnlock()
+
 	ids := make([]uint, 0, len(ws.words))
-	for id := range ws.words {
-		ids = append(ids, id)
+	for _, deckId := range deckIds {
+		deck, ok := ws.decks[deckId]
+		if !ok {
+			continue
+		}
+		for _, word := range deck.Words {
+			ids = append(ids, word.ID)
+		}
 	}
 	rand.Shuffle(len(ids), func(i, j int) {
 		ids[i], ids[j] = ids[j], ids[i]
@@ -49,6 +103,9 @@ func (ws *WordStorage) GetShuffledIds() []uint {
 }
 
 func (ws *WordStorage) GetWordsByIds(ids []uint) []*TabooWord {
+	ws.wordMtx.RLock()
+	defer ws.wordMtx.RUnlock()
+
 	words := make([]*TabooWord, 0, len(ids))
 	for _, id := range ids {
 		word, ok := ws.words[id]
@@ -60,25 +117,178 @@ func (ws *WordStorage) GetWordsByIds(ids []uint) []*TabooWord {
 	return words
 }
 
-func (ws *WordStorage) loadWords(file string) error {
-	data, err := os.ReadFile(file)
-	if err != nil {
-		return fmt.Errorf("failed to read word file %s; %w", file, err)
+// HasDeck reports whether the deck exists and is available to the given room.
+func (ws *WordStorage) HasDeck(deckId string, roomCode string) bool {
+	ws.wordMtx.RLock()
+	defer ws.wordMtx.RUnlock()
+
+	deck, ok := ws.decks[deckId]
+	return ok && deck.IsAvailable(roomCode)
+}
+
+// GetDeckIds returns sorted IDs of the shared decks loaded from disk.
+func (ws *WordStorage) GetDeckIds() []string {
+	ws.wordMtx.RLock()
+	defer ws.wordMtx.RUnlock()
+	return ws.getDeckIdsUnlocked("")
+}
+
+func (ws *WordStorage) getDeckIdsUnlocked(roomCode string) []string {
+	ids := make([]string, 0, len(ws.decks))
+	for id, deck := range ws.decks {
+		if deck.IsAvailable(roomCode) {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// GetDeckInfos returns the shared decks followed by decks uploaded to the given room.
+func (ws *WordStorage) GetDeckInfos(roomCode string) []DeckInfo {
+	ws.wordMtx.RLock()
+	defer ws.wordMtx.RUnlock()
+
+	infos := make([]DeckInfo, 0, len(ws.decks))
+	for _, id := range ws.getDeckIdsUnlocked(roomCode) {
+		infos = append(infos, ws.decks[id].CreateDeckInfo())
+	}
+	return infos
+}
+
+// AddRoomDeck registers words uploaded by players as a deck available only to the given room.
+func (ws *WordStorage) AddRoomDeck(roomCode string, title string, words []*TabooWord) *Deck {
+	ws.wordMtx.Lock()
+	defer ws.wordMtx.Unlock()
+
+	for _, word := range words {
+		word.ID = ws.nextCustomId
+		ws.nextCustomId++
+	}
+
+	deckId := fmt.Sprintf("%s-%d", strings.ToLower(roomCode), words[0].ID)
+	deck := &Deck{
+		Id:       deckId,
+		Owner:    roomCode,
+		Title:    title,
+		Language: "",
+		Category: "custom",
+		Words:    words,
+	}
+	ws.addDeck(deck)
+	return deck
+}
+
+// RestoreRoomDeck registers a previously uploaded deck keeping its word IDs.
+func (ws *WordStorage) RestoreRoomDeck(deck *Deck) {
+	ws.wordMtx.Lock()
+	defer ws.wordMtx.Unlock()
+
+	for _, word := range deck.Words {
+		if word.ID >= ws.nextCustomId {
+			ws.nextCustomId = word.ID + 1
+		}
+	}
+	ws.addDeck(deck)
+}
+
+func (ws *WordStorage) GetRoomDecks(roomCode string) []*Deck {
+	ws.wordMtx.RLock()
+	defer ws.wordMtx.RUnlock()
+
+	decks := []*Deck{}
+	for _, deck := range ws.decks {
+		if deck.Owner == roomCode {
+			decks = append(decks, deck)
+		}
 	}
+	return decks
+}
 
-	var list []*TabooWord
-	err = json.Unmarshal(data, &list)
+func (ws *WordStorage) RemoveRoomDecks(roomCode string) {
+	ws.wordMtx.Lock()
+	defer ws.wordMtx.Unlock()
+
+	for id, deck := range ws.decks {
+		if deck.Owner != roomCode {
+			continue
+		}
+		for _, word := range deck.Words {
+			delete(ws.words, word.ID)
+		}
+		delete(ws.decks, id)
+	}
+}
+
+func (ws *WordStorage) loadDecks(dir string) error {
+	files, err := os.ReadDir(dir)
 	if err != nil {
-		return fmt.Errorf("failed to unmarshal word file contents: %w", err)
+		return fmt.Errorf("failed to read deck dir %s: %w", dir, err)
 	}
 
-	for _, word := range list {
-		wordStorage.words[word.ID] = word
+	for _, file := range files {
+		// Skip directories and non-json files
+		if file.IsDir() || !strings.HasSuffix(strings.ToLower(file.Name()), ".json") {
+			continue
+		}
+
+		path := filepath.Join(dir, file.Name())
+		deck, err := loadDeck(path)
+		if err != nil {
+			return err
+		}
+		deck.Id = strings.TrimSuffix(file.Name(), filepath.Ext(file.Name()))
+		deck.Owner = ""
+		ws.addDeck(deck)
+		slog.Debug("Added new deck to storage.", "id", deck.Id, "words", len(deck.Words))
 	}
 
+	if len(ws.decks) == 0 {
+		return fmt.Errorf("no word decks found in %s", dir)
+	}
 	return nil
 }
 
-func (ws WordStorage) GetWordCount() uint {
-	return uint(len(ws.words))
+// addDeck registers the deck words, dropping words whose ID is already used by another deck.
+func (ws *WordStorage) addDeck(deck *Deck) {
+	words := make([]*TabooWord, 0, len(deck.Words))
+	for _, word := range deck.Words {
+		if _, exists := ws.words[word.ID]; exists {
+			slog.Warn("Duplicate word ID, skipping word.", "deck", deck.Id, "id", word.ID, "word", word.Word)
+			continue
+		}
+		ws.words[word.ID] = word
+		words = append(words, word)
+	}
+	deck.Words = words
+	ws.decks[deck.Id] = deck
+}
+
+func loadDeck(file string) (*Deck, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read deck file %s; %w", file, err)
+	}
+
+	var deck Deck
+	err = json.Unmarshal(data, &deck)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal deck file %s contents: %w", file, err)
+	}
+
+	return &deck, nil
+}
+
+func (d Deck) IsAvailable(roomCode string) bool {
+	return d.Owner == "" || d.Owner == roomCode
+}
+
+func (d Deck) CreateDeckInfo() DeckInfo {
+	return DeckInfo{
+		Id:        d.Id,
+		Title:     d.Title,
+		Language:  d.Language,
+		Category:  d.Category,
+		WordCount: len(d.Words),
+	}
 }
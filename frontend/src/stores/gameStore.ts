@@ -1,44 +1,55 @@
-import { GameState } from '@/types/messages';
+import { GameState, type Scores } from '@/types/messages';
 import { Team } from '@/types/player';
 import { defineStore } from 'pinia';
 import { computed, ref, type Ref } from 'vue';
 
 export const useGameStore = defineStore('game', () => {
+  const roomCode: Ref<string | null> = ref(null);
   const gameState: Ref<GameState> = ref(GameState.InLobby);
-  const redScore: Ref<number> = ref(0);
-  const blueScore: Ref<number> = ref(0);
+  const scores: Ref<Scores> = ref({});
   const currentTeam: Ref<Team | null> = ref(null);
-  const guesserId: Ref<string | null> = ref(null);
+  const guesserIds: Ref<string[]> = ref([]);
   const hintGiverId: Ref<string | null> = ref(null);
   const duration: Ref<number> = ref(60);
+  // scores can be negative after penalties, no scores yet count as 0
+  const topScore = computed(() => {
+    const values = Object.values(scores.value);
+    return values.length > 0 ? Math.max(...values) : 0;
+  });
   const winner = computed(() => {
-    if (redScore.value > blueScore.value) {
-      return Team.Red;
-    } else if (blueScore.value > redScore.value) {
-      return Team.Blue;
-    } else {
-      return null;
-    }
+    const leaders = Object.keys(scores.value)
+      .map(Number)
+      .filter(team => scores.value[team] === topScore.value);
+    // a tie has no winner
+    return leaders.length === 1 ? leaders[0] as Team : null;
   });
 
+  function setRoomCode(code: string | null): void {
+    roomCode.value = code;
+  }
+
   function setGameState(state: GameState): void {
     gameState.value = state;
   }
 
-  function setRedScore(score: number): void {
-    redScore.value = score;
+  function setScores(newScores: Scores): void {
+    scores.value = newScores;
   }
 
-  function setBlueScore(score: number): void {
-    blueScore.value = score;
+  function getScore(team: Team): number {
+    return scores.value[team] ?? 0;
   }
 
   function setCurrentTeam(team: Team | null): void {
     currentTeam.value = team;
   }
 
-  function setGuesserId(id: string | null): void {
-    guesserId.value = id;
+  function setGuesserIds(ids: string[]): void {
+    guesserIds.value = ids;
+  }
+
+  function isGuesser(id: string | null): boolean {
+    return id !== null && guesserIds.value.includes(id);
   }
 
   function setHintGiverId(id: string | null): void {
@@ -51,25 +62,27 @@ export const useGameStore = defineStore('game', () => {
 
   function resetGame(): void {
     gameState.value = GameState.InLobby;
-    redScore.value = 0;
-    blueScore.value = 0;
+    scores.value = {};
     currentTeam.value = null;
-    guesserId.value = null;
+    guesserIds.value = [];
     hintGiverId.value = null;
     duration.value = 60;
   }
 
   return {
+    roomCode,
+    setRoomCode,
     gameState,
     setGameState,
-    redScore,
-    setRedScore,
-    blueScore,
-    setBlueScore,
+    scores,
+    setScores,
+    getScore,
+    topScore,
     currentTeam,
     setCurrentTeam,
-    guesserId,
-    setGuesserId,
+    guesserIds,
+    setGuesserIds,
+    isGuesser,
     hintGiverId,
     setHintGiverId,
     duration,
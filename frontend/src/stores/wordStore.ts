@@ -1,17 +1,28 @@
-import type { Word } from '@/types/words';
+import { isRevealed, type RedactedWord, type Word } from '@/types/words';
 import Denque from 'denque';
 import { defineStore } from 'pinia';
 import { computed, ref, type ComputedRef, type Ref } from 'vue';
 
 export const useWordStore = defineStore('wordStore', () => {
-  const words: Ref<Denque<Word>> = ref(new Denque<Word>());
+  const words: Ref<Denque<Word | RedactedWord>> = ref(new Denque<Word | RedactedWord>());
+  // current word if this player may see it
   const currentWord: ComputedRef<Word | null> = computed(() => {
-    return words.value.peekFront() || null;
+    const word = words.value.peekFront();
+    if (!word || !isRevealed(word)) {
+      return null;
+    }
+    return word;
   });
 
-  function addWords(newWords: Word[]): void {
+  function addWords(newWords: (Word | RedactedWord)[]): void {
     for (const word of newWords) {
-      words.value.push(word);
+      // words already queued are revealed once the player may see them
+      const idx = words.value.toArray().findIndex(item => item.id === word.id);
+      if (idx === -1) {
+        words.value.push(word);
+      } else {
+        words.value.splice(idx, 1, word);
+      }
     }
   }
 
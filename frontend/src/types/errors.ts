@@ -7,5 +7,26 @@ export enum ErrCodes {
   PlayerNotInTeam,
   GameNotStarted,
   NotHintGive,
+  NotAllConnected,
   RoundNotActive,
+  RoundNotPaused,
+  GameNotEnded,
+  RoomNotFound,
+  NotHost,
+  InvalidSettings,
+  InvalidTeam,
+  DeckNotFound,
+  InvalidWordList,
+  NotOpposingTeam,
+  InvalidCredentials,
+  GameHistoryNotFound,
+  SpectatorAction,
+  TargetNotFound,
+  TeamsLocked,
+  TeamsNotFilled,
+  CannotKickSelf,
+  PauseLimitReached,
+  TooManyAttempts,
+  TooManyRooms,
+  RoomLimitReached,
 }
@@ -1,5 +1,5 @@
 import type { OtherPlayer, Team } from './player';
-import type { Word } from './words';
+import type { RedactedWord, Word } from './words';
 
 export enum MessageType {
   // general messages
@@ -9,6 +9,11 @@ export enum MessageType {
   ConnectAckMsg = 'connect_ack',
   ReconnectMsg = 'reconnect',
   ReconnectAckMsg = 'reconnect_ack',
+  // rooms
+  CreateRoomMsg = 'create_room',
+  RoomCreatedMsg = 'room_created',
+  JoinRoomMsg = 'join_room',
+  RoomListMsg = 'room_list',
   PlayerJoinedMsg = 'player_joined',
   PlayerLeftMsg = 'player_left',
   PlayerDisconnectedMsg = 'player_disconnected',
@@ -46,6 +51,9 @@ export enum GameState {
   Ended,
 }
 
+// Team scores keyed by team
+export type Scores = Record<number, number>;
+
 export interface MessageBase {
   type: string;
 }
@@ -60,6 +68,8 @@ export interface ErrorResponseMessage extends MessageBase {
 export interface ConnectMessage extends MessageBase {
   type: MessageType.ConnectMsg;
   name: string;
+  // joins an existing room, a new room is created when omitted
+  roomCode?: string;
 }
 
 export interface ConnectAckMessage extends MessageBase {
@@ -67,6 +77,7 @@ export interface ConnectAckMessage extends MessageBase {
   playerId: string;
   sessionToken: string;
   name: string;
+  roomCode: string;
 }
 
 export interface ReconnectMessage extends MessageBase {
@@ -81,11 +92,39 @@ export interface ReconnectAckMessage extends Omit<ConnectAckMessage, 'type'> {
   state: GameState.InProgress | GameState.InRound | GameState.RoundPaused;
   remainingDuration: number;
   currentTeam: Team.Red | Team.Blue;
-  guesserId: string;
+  guesserIds: string[];
   hintGiverId: string;
-  redScore: number;
-  blueScore: number;
-  words: Word[]
+  scores: Scores;
+  // guessers only receive word IDs
+  words: (Word | RedactedWord)[];
+}
+
+export interface CreateRoomMessage extends MessageBase {
+  type: MessageType.CreateRoomMsg;
+}
+
+export interface RoomCreatedMessage extends MessageBase {
+  type: MessageType.RoomCreatedMsg;
+  roomCode: string;
+}
+
+export interface JoinRoomMessage extends MessageBase {
+  type: MessageType.JoinRoomMsg;
+  name: string;
+  roomCode: string;
+}
+
+export interface RoomInfo {
+  roomCode: string;
+  playerCount: number;
+  maxPlayers: number;
+  state: GameState;
+}
+
+// sent without rooms to request the list
+export interface RoomListMessage extends MessageBase {
+  type: MessageType.RoomListMsg;
+  rooms?: RoomInfo[];
 }
 
 export interface PlayerLeftMessage extends MessageBase {
@@ -139,7 +178,8 @@ export interface GameStateChangedMessage extends MessageBase {
 
 export interface WordListMessage extends MessageBase {
   type: MessageType.WordListMsg;
-  words: Word[];
+  // guessers only receive word IDs
+  words: (Word | RedactedWord)[];
 }
 
 export interface SkipWordMessage extends MessageBase {
@@ -150,6 +190,7 @@ export interface SkipWordMessage extends MessageBase {
 export interface WordSkippedMessage extends MessageBase {
   type: MessageType.WordSkippedMsg;
   playerId: string;
+  scores: Scores;
 }
 
 export interface GuessWordMessage extends MessageBase {
@@ -160,17 +201,17 @@ export interface GuessWordMessage extends MessageBase {
 export interface WordGuessedMessage extends MessageBase {
   type: MessageType.WordGuessedMsg;
   playerId: string;
-  redScore: number;
-  blueScore: number;
+  scores: Scores;
 }
 
 export interface RoundSetupMessage extends MessageBase {
   type: MessageType.RoundSetupMsg;
   team: Team;
-  guesserId: string;
+  guesserIds: string[];
   hintGiverId: string;
   duration: number;
-  words: Word[];
+  // guessers only receive word IDs
+  words: (Word | RedactedWord)[];
 }
 
 export interface RoundStartedMessage extends MessageBase {
@@ -200,8 +241,7 @@ export interface RoundResumedMessage extends MessageBase {
 
 export interface GameEndedMessage extends MessageBase {
   type: MessageType.GameEndedMsg;
-  redScore: number;
-  blueScore: number;
+  scores: Scores;
 }
 
 export interface ResetGameMessage extends MessageBase {
@@ -3,3 +3,12 @@ export interface Word {
   word: string;
   taboo: string[];
 }
+
+// Word sent to guessers, who only learn its ID
+export interface RedactedWord {
+  id: number;
+}
+
+export function isRevealed(word: Word | RedactedWord): word is Word {
+  return 'word' in word;
+}